@@ -0,0 +1,153 @@
+package bouncer
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	RoleCheck = "check"
+	RoleAdmin = "admin"
+)
+
+// checkMethods are open to the check role, every other Bouncer and
+// BouncerAdmin method needs the admin role.
+var checkMethods = map[string]bool{
+	"/bouncer.Bouncer/Authorization":   true,
+	"/bouncer.Bouncer/AuthorizeBatch":  true,
+	"/bouncer.Bouncer/AuthorizeStream": true,
+}
+
+// ClientConfig is a known client. It is identified by the bearer token in
+// the authorization metadata or by the subject of its verified client
+// certificate, either the common name or the full distinguished name.
+type ClientConfig struct {
+	Name    string
+	Token   string
+	Subject string
+	Roles   []string
+}
+
+// AccessConfig restricts the calls to the listed clients. Access is not
+// checked when no clients are listed.
+type AccessConfig struct {
+	Clients []ClientConfig
+}
+
+type identityKey struct{}
+
+func requiredRole(fullMethod string) string {
+	if checkMethods[fullMethod] {
+		return RoleCheck
+	}
+	if isBouncerMethod(fullMethod) {
+		return RoleAdmin
+	}
+	return ""
+}
+
+func (c ClientConfig) hasRole(role string) bool {
+	for _, clientRole := range c.Roles {
+		if clientRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if strings.HasPrefix(value, "Bearer ") {
+			return strings.TrimPrefix(value, "Bearer ")
+		}
+	}
+	return ""
+}
+
+func certSubjects(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	subject := info.State.VerifiedChains[0][0].Subject
+	return []string{subject.CommonName, subject.String()}
+}
+
+// identify returns the client making the call, the token is checked first.
+func (config AccessConfig) identify(ctx context.Context) (ClientConfig, bool) {
+	if token := bearerToken(ctx); token != "" {
+		for _, client := range config.Clients {
+			if client.Token != "" && subtle.ConstantTimeCompare([]byte(client.Token), []byte(token)) == 1 {
+				return client, true
+			}
+		}
+	}
+	for _, subject := range certSubjects(ctx) {
+		for _, client := range config.Clients {
+			if client.Subject != "" && client.Subject == subject {
+				return client, true
+			}
+		}
+	}
+	return ClientConfig{}, false
+}
+
+// checkAccess rejects the call with codes.PermissionDenied unless the caller
+// has the role the method needs. The returned context carries the caller name.
+func (s *Service) checkAccess(ctx context.Context, fullMethod string) (context.Context, error) {
+	config := s.currentConfig().Access
+	if len(config.Clients) == 0 {
+		return ctx, nil
+	}
+	role := requiredRole(fullMethod)
+	if role == "" {
+		return ctx, nil
+	}
+
+	client, ok := config.identify(ctx)
+	if !ok {
+		return ctx, status.Error(codes.PermissionDenied, "unknown client")
+	}
+	if !client.hasRole(role) {
+		return ctx, status.Errorf(codes.PermissionDenied, "client %s has no %s role", client.Name, role)
+	}
+	return context.WithValue(ctx, identityKey{}, client.Name), nil
+}
+
+func (s *Service) accessUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.checkAccess(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *Service) accessStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.checkAccess(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &identifiedStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,95 @@
+package bouncer
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccess(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := ca.issue(t, dir, "server")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.Nil(t, ioutil.WriteFile(caFile, ca.pem, 0o600))
+
+	service := newTestService(t, func(config *ConfigStruct) {
+		config.TLS = TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}
+		config.Access = AccessConfig{Clients: []ClientConfig{
+			{Name: "web", Token: "web-token", Roles: []string{RoleCheck}},
+			{Name: "ops", Subject: "ops", Roles: []string{RoleCheck, RoleAdmin}},
+		}}
+	})
+	require.Nil(t, service.config.validate())
+	startTestService(t, service, false)
+
+	dial := func(t *testing.T, commonName string) *grpc.ClientConn {
+		cert, err := tls.LoadX509KeyPair(ca.issue(t, t.TempDir(), commonName))
+		require.Nil(t, err)
+		conn, err := grpc.Dial(service.listener.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      ca.pool(),
+			Certificates: []tls.Certificate{cert},
+		})))
+		require.Nil(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	authRequest := &AuthRequest{Login: "login", Password: "password", Ip: "198.51.100.1"}
+
+	t.Run("unknown client", func(t *testing.T) {
+		conn := dial(t, "stranger")
+		ctx := context.Background()
+		_, err := NewBouncerClient(conn).Authorization(ctx, authRequest)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong-token")
+		_, err = NewBouncerClient(conn).Authorization(ctx, authRequest)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.Nil(t, err)
+	})
+
+	t.Run("check role by token", func(t *testing.T) {
+		client := NewBouncerClient(dial(t, "stranger"))
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer web-token")
+		_, err := client.Authorization(ctx, authRequest)
+		require.Nil(t, err)
+		_, err = client.AuthorizeBatch(ctx, &AuthBatch{Items: []*AuthItem{{RequestId: "1", Request: authRequest}}})
+		require.Nil(t, err)
+
+		_, err = client.AddWhiteList(ctx, &Subnet{Subnet: "0.0.0.0/0"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = client.DropBucket(ctx, &DropBucketParams{Login: "login", Ip: "198.51.100.1"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("admin role by certificate", func(t *testing.T) {
+		client := NewBouncerClient(dial(t, "ops"))
+		ctx := context.Background()
+		_, err := client.Authorization(ctx, authRequest)
+		require.Nil(t, err)
+		_, err = client.AddBlackList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Nil(t, err)
+		_, err = client.DropBucket(ctx, &DropBucketParams{Login: "login", Ip: "198.51.100.1"})
+		require.Nil(t, err)
+	})
+
+	t.Run("invalid clients", func(t *testing.T) {
+		config := service.currentConfig()
+		config.Access = AccessConfig{Clients: []ClientConfig{{Name: "web", Roles: []string{RoleCheck}}}}
+		require.Error(t, config.validate())
+		config.Access = AccessConfig{Clients: []ClientConfig{{Name: "web", Token: "token", Roles: []string{"root"}}}}
+		require.Error(t, config.validate())
+	})
+}
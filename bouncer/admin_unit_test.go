@@ -0,0 +1,81 @@
+package bouncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminListener(t *testing.T) {
+	startServer := func(t *testing.T, separate bool, disableLegacy bool) (*Service, *grpc.ClientConn, *grpc.ClientConn) {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.DisableLegacyAdmin = disableLegacy
+		})
+		startTestService(t, service, separate)
+		conn := dialTestService(t, service.listener)
+		if !separate {
+			return service, conn, conn
+		}
+		return service, conn, dialTestService(t, service.adminListener)
+	}
+	ctx := context.Background()
+	subnet := &Subnet{Subnet: "192.0.2.0/24"}
+	authRequest := &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"}
+
+	t.Run("separate listener", func(t *testing.T) {
+		service, conn, adminConn := startServer(t, true, false)
+
+		_, err := NewBouncerAdminClient(adminConn).AddBlackList(ctx, subnet)
+		require.Nil(t, err)
+		_, err = NewBouncerAdminClient(conn).RemoveBlackList(ctx, subnet)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = NewBouncerClient(adminConn).Authorization(ctx, authRequest)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+
+		response, err := NewBouncerClient(conn).Authorization(ctx, authRequest)
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+
+		require.Nil(t, service.ShutDown())
+		_, err = NewBouncerAdminClient(adminConn).RemoveBlackList(ctx, subnet)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("shared listener keeps the old RPCs", func(t *testing.T) {
+		service, conn, _ := startServer(t, false, false)
+		defer service.ShutDown()
+
+		_, err := NewBouncerAdminClient(conn).AddWhiteList(ctx, subnet)
+		require.Nil(t, err)
+		_, err = NewBouncerClient(conn).RemoveWhiteList(ctx, subnet)
+		require.Nil(t, err)
+		_, err = NewBouncerClient(conn).AddBlackList(ctx, subnet)
+		require.Nil(t, err)
+
+		response, err := NewBouncerClient(conn).Authorization(ctx, authRequest)
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+	})
+
+	t.Run("legacy admin methods disabled", func(t *testing.T) {
+		_, conn, adminConn := startServer(t, true, true)
+		client := NewBouncerClient(conn)
+
+		_, err := client.AddBlackList(ctx, subnet)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = client.RemoveWhiteList(ctx, subnet)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = client.DropBucket(ctx, &DropBucketParams{Login: "login"})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+
+		_, err = NewBouncerAdminClient(adminConn).AddBlackList(ctx, subnet)
+		require.Nil(t, err)
+		response, err := client.Authorization(ctx, authRequest)
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+	})
+}
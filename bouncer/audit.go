@@ -0,0 +1,230 @@
+package bouncer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	AuditSinkNone   = ""
+	AuditSinkStdout = "stdout"
+	AuditSinkFile   = "file"
+)
+
+const (
+	AuditLevelInfo = "info"
+	AuditLevelWarn = "warn"
+)
+
+const (
+	auditAuthorization = "authorization"
+	auditListAdd       = "list_add"
+	auditListRemove    = "list_remove"
+	auditDropBucket    = "drop_bucket"
+	auditEscalation    = "escalation"
+)
+
+// AuditConfig sets where the audit log is written. With Level "warn" allowed
+// authorizations are left out. File sinks are rotated once they grow over
+// MaxSizeMB.
+type AuditConfig struct {
+	Sink       string
+	Level      string
+	Path       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
+
+// auditRecord is one JSON line of the audit log. Logins and passwords are
+// never written as is, only their bucket keys.
+type auditRecord struct {
+	Time         time.Time `json:"time"`
+	Level        string    `json:"level"`
+	Event        string    `json:"event"`
+	Caller       string    `json:"caller,omitempty"`
+	LoginHash    string    `json:"login_hash,omitempty"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	Ok           *bool     `json:"ok,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	List         string    `json:"list,omitempty"`
+	Subnet       string    `json:"subnet,omitempty"`
+	Ban          int       `json:"ban,omitempty"`
+	BanDuration  string    `json:"ban_duration,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+type auditLog struct {
+	lock     sync.Mutex
+	writer   io.Writer
+	closer   io.Closer
+	warnOnly bool
+}
+
+func newAuditLog(config AuditConfig) (*auditLog, error) {
+	audit := &auditLog{}
+	switch config.Level {
+	case "", AuditLevelInfo:
+	case AuditLevelWarn:
+		audit.warnOnly = true
+	default:
+		return nil, errors.Errorf("Unknown audit level %q", config.Level)
+	}
+
+	switch config.Sink {
+	case AuditSinkNone:
+		return nil, nil
+	case AuditSinkStdout:
+		audit.writer = os.Stdout
+	case AuditSinkFile:
+		if config.Path == "" {
+			return nil, errors.New("Audit file path is empty")
+		}
+		file := &lumberjack.Logger{
+			Filename:   config.Path,
+			MaxSize:    config.MaxSizeMB,
+			MaxBackups: config.MaxBackups,
+			MaxAge:     config.MaxAgeDays,
+		}
+		audit.writer, audit.closer = file, file
+	default:
+		return nil, errors.Errorf("Unknown audit sink %q", config.Sink)
+	}
+	return audit, nil
+}
+
+func (a *auditLog) write(record auditRecord) error {
+	if a == nil || (a.warnOnly && record.Level == AuditLevelInfo) {
+		return nil
+	}
+	record.Time = time.Now().UTC()
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, err = a.writer.Write(append(line, '\n'))
+	return err
+}
+
+// writeAudit counts and logs the records that could not be written, the
+// audit log must not lose them silently.
+func (s *Service) writeAudit(record auditRecord) {
+	if err := s.audit.write(record); err != nil {
+		s.metrics.observeAuditError()
+		s.auditErrors.log(errors.Wrapf(err, "Writing %s audit record", record.Event), time.Now())
+	}
+}
+
+func (a *auditLog) Close() error {
+	if a == nil || a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+func (s *Service) initAudit() error {
+	audit, err := newAuditLog(s.config.Audit)
+	if err != nil {
+		return errors.Wrap(err, "Opening audit log")
+	}
+	s.audit = audit
+	return nil
+}
+
+func (s *Service) auditAuthorization(ctx context.Context, ip string, buckets []authBucket, response *AuthResponse) {
+	record := auditRecord{
+		Level:  AuditLevelInfo,
+		Event:  auditAuthorization,
+		Caller: callerIdentity(ctx),
+		IP:     ip,
+		Ok:     &response.Ok,
+		Reason: response.Reason.String(),
+	}
+	if !response.Ok {
+		record.Level = AuditLevelWarn
+	}
+	for _, bucket := range buckets {
+		switch bucket.bucketType {
+		case "login":
+			record.LoginHash = bucket.bucketKey
+		case "password":
+			record.PasswordHash = bucket.bucketKey
+		}
+	}
+	s.writeAudit(record)
+}
+
+func (s *Service) auditListChange(ctx context.Context, event string, listType string, subnet string, err error) {
+	if len(subnet) > maxAddressLength {
+		subnet = subnet[:maxAddressLength]
+	}
+	record := auditRecord{
+		Level:  AuditLevelWarn,
+		Event:  event,
+		Caller: callerIdentity(ctx),
+		List:   listType,
+		Subnet: subnet,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.writeAudit(record)
+}
+
+func (s *Service) auditDropBucket(ctx context.Context, ip string, loginKey string, err error) {
+	if len(ip) > maxAddressLength {
+		ip = ip[:maxAddressLength]
+	}
+	record := auditRecord{
+		Level:     AuditLevelWarn,
+		Event:     auditDropBucket,
+		Caller:    callerIdentity(ctx),
+		LoginHash: loginKey,
+		IP:        ip,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.writeAudit(record)
+}
+
+func (s *Service) auditEscalation(ctx context.Context, ip string, subnet string, ban time.Duration, bans int, err error) {
+	record := auditRecord{
+		Level:       AuditLevelWarn,
+		Event:       auditEscalation,
+		Caller:      callerIdentity(ctx),
+		IP:          ip,
+		List:        "black",
+		Subnet:      subnet,
+		Ban:         bans,
+		BanDuration: ban.String(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.writeAudit(record)
+}
+
+// callerIdentity names the client of the call by its configured name, or by
+// its network address when access is not checked.
+func callerIdentity(ctx context.Context) string {
+	if name, ok := ctx.Value(identityKey{}).(string); ok {
+		return name
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
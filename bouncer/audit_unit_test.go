@@ -0,0 +1,140 @@
+package bouncer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestAuditLog(t *testing.T) {
+	const testLogin = "login"
+	const testPassword = "s3cr3t-passw0rd"
+
+	newService := func(t *testing.T, level string) (*Service, string) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.Limit["login"] = 1
+			config.Audit = AuditConfig{Sink: AuditSinkFile, Level: level, Path: path}
+		})
+		service.initValues()
+		require.Nil(t, service.initAudit())
+		return service, path
+	}
+	readRecords := func(t *testing.T, path string) []auditRecord {
+		file, err := os.Open(path)
+		require.Nil(t, err)
+		defer file.Close()
+
+		records := []auditRecord{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			require.NotContains(t, scanner.Text(), testPassword)
+			record := auditRecord{}
+			require.Nil(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+	ctx := context.Background()
+	request := &AuthRequest{Login: testLogin, Password: testPassword, Ip: "198.51.100.1"}
+
+	t.Run("decisions and list changes", func(t *testing.T) {
+		service, path := newService(t, AuditLevelInfo)
+		for i := 0; i < 2; i++ {
+			_, err := service.Authorization(ctx, request)
+			require.Nil(t, err)
+		}
+		_, err := service.AddBlackList(ctx, &Subnet{Subnet: "192.0.2.7/24"})
+		require.Nil(t, err)
+		_, err = service.RemoveWhiteList(ctx, &Subnet{Subnet: "invalid"})
+		require.Error(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: testLogin, Ip: "::ffff:198.51.100.1"})
+		require.Nil(t, err)
+		require.Nil(t, service.audit.Close())
+
+		records := readRecords(t, path)
+		require.Len(t, records, 5)
+		loginKey, _ := service.hasher.hashKey(testLogin, false)
+		passwordKey, _ := service.hasher.hashKey(testPassword, false)
+
+		require.Equal(t, auditAuthorization, records[0].Event)
+		require.Equal(t, AuditLevelInfo, records[0].Level)
+		require.Equal(t, loginKey, records[0].LoginHash)
+		require.Equal(t, passwordKey, records[0].PasswordHash)
+		require.Equal(t, "198.51.100.1", records[0].IP)
+		require.True(t, *records[0].Ok)
+
+		require.Equal(t, AuditLevelWarn, records[1].Level)
+		require.False(t, *records[1].Ok)
+		require.Equal(t, Reason_LOGIN_LIMIT.String(), records[1].Reason)
+
+		require.Equal(t, auditRecord{Time: records[2].Time, Level: AuditLevelWarn, Event: auditListAdd, List: "black", Subnet: "192.0.2.0/24"}, records[2])
+		require.Equal(t, auditListRemove, records[3].Event)
+		require.NotEmpty(t, records[3].Error)
+		require.Equal(t, auditDropBucket, records[4].Event)
+		require.Equal(t, loginKey, records[4].LoginHash)
+		require.Equal(t, "198.51.100.1", records[4].IP)
+	})
+
+	t.Run("warn level skips allowed attempts", func(t *testing.T) {
+		service, path := newService(t, AuditLevelWarn)
+		for i := 0; i < 2; i++ {
+			_, err := service.Authorization(ctx, request)
+			require.Nil(t, err)
+		}
+		require.Nil(t, service.audit.Close())
+
+		records := readRecords(t, path)
+		require.Len(t, records, 1)
+		require.False(t, *records[0].Ok)
+	})
+
+	t.Run("failed drops", func(t *testing.T) {
+		service, path := newService(t, AuditLevelWarn)
+		_, err := service.DropBucket(ctx, &DropBucketParams{Login: testLogin})
+		require.Error(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Ip: "localhost"})
+		require.Error(t, err)
+		require.Nil(t, service.audit.Close())
+
+		records := readRecords(t, path)
+		require.Len(t, records, 2)
+		loginKey, _ := service.hasher.hashKey(testLogin, false)
+		require.Equal(t, auditDropBucket, records[0].Event)
+		require.Equal(t, loginKey, records[0].LoginHash)
+		require.NotEmpty(t, records[0].Error)
+		require.Equal(t, "localhost", records[1].IP)
+		require.NotEmpty(t, records[1].Error)
+	})
+
+	t.Run("write errors are counted", func(t *testing.T) {
+		service, _ := newService(t, AuditLevelInfo)
+		require.Nil(t, service.audit.Close())
+		service.audit.writer = failingWriter{}
+		_, err := service.Authorization(ctx, request)
+		require.Nil(t, err)
+		require.Equal(t, float64(1), testutil.ToFloat64(service.metrics.auditErrors))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newAuditLog(AuditConfig{Sink: "syslog"})
+		require.Error(t, err)
+		_, err = newAuditLog(AuditConfig{Sink: AuditSinkStdout, Level: "debug"})
+		require.Error(t, err)
+		_, err = newAuditLog(AuditConfig{Sink: AuditSinkFile})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,84 @@
+package bouncer
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxBatchSize       = 1000
+	maxRequestIDLength = 128
+)
+
+// authorizeItem evaluates the item like Authorization. An invalid item gets
+// its status in the result instead of failing the whole call.
+func (s *Service) authorizeItem(ctx context.Context, item *AuthItem) *AuthResult {
+	result := &AuthResult{}
+	err := checkField("request id", item.RequestId, false, maxRequestIDLength)
+	if err == nil {
+		result.RequestId = item.RequestId
+		if item.Request == nil {
+			err = status.Error(codes.InvalidArgument, "request is required")
+		} else {
+			result.Response, err = s.Authorization(ctx, item.Request)
+		}
+	}
+	if err != nil {
+		st := status.Convert(err)
+		result.Code = int32(st.Code())
+		result.Error = st.Message()
+	}
+	return result
+}
+
+func (s *Service) AuthorizeBatch(ctx context.Context, in *AuthBatch) (*AuthBatchResult, error) {
+	if len(in.Items) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch has %d items, %d at most", len(in.Items), maxBatchSize)
+	}
+	response := &AuthBatchResult{Results: make([]*AuthResult, 0, len(in.Items))}
+	for _, item := range in.Items {
+		response.Results = append(response.Results, s.authorizeItem(ctx, item))
+	}
+	return response, nil
+}
+
+// AuthorizeStream answers the items one by one until the client closes its
+// side. On shutdown the stream ends with codes.Unavailable, the items that
+// got no result yet have to be sent again.
+func (s *Service) AuthorizeStream(stream Bouncer_AuthorizeStreamServer) error {
+	items := make(chan *AuthItem)
+	received := make(chan error, 1)
+	go func() {
+		for {
+			item, err := stream.Recv()
+			if err != nil {
+				received <- err
+				return
+			}
+			select {
+			case items <- item:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case item := <-items:
+			if err := stream.Send(s.authorizeItem(stream.Context(), item)); err != nil {
+				return err
+			}
+		case err := <-received:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-s.draining():
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
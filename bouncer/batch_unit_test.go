@@ -0,0 +1,101 @@
+package bouncer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeBatch(t *testing.T) {
+	startServer := func(t *testing.T) (*Service, BouncerClient) {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.Limit["login"] = 2
+			config.ShutdownTimeoutSec = 5
+		})
+		startTestService(t, service, false)
+		return service, NewBouncerClient(dialTestService(t, service.listener))
+	}
+	ctx := context.Background()
+	item := func(id string, login string, ip string) *AuthItem {
+		return &AuthItem{RequestId: id, Request: &AuthRequest{Login: login, Password: "password", Ip: ip}}
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		_, client := startServer(t)
+		response, err := client.AuthorizeBatch(ctx, &AuthBatch{Items: []*AuthItem{
+			item("1", "login", "192.0.2.1"),
+			item("2", "login", "192.0.2.1"),
+			item("3", "login", "192.0.2.1"),
+			item("4", "login", "localhost"),
+			{RequestId: "5"},
+			item(strings.Repeat("6", maxRequestIDLength+1), "other", "192.0.2.1"),
+		}})
+		require.Nil(t, err)
+		require.Len(t, response.Results, 6)
+
+		for i, result := range response.Results[:3] {
+			require.Equal(t, fmt.Sprint(i+1), result.RequestId)
+			require.Equal(t, int32(codes.OK), result.Code)
+		}
+		require.True(t, response.Results[1].Response.Ok)
+		require.False(t, response.Results[2].Response.Ok)
+		require.Equal(t, Reason_LOGIN_LIMIT, response.Results[2].Response.Reason)
+
+		require.Equal(t, "4", response.Results[3].RequestId)
+		require.Equal(t, int32(codes.InvalidArgument), response.Results[3].Code)
+		require.NotEmpty(t, response.Results[3].Error)
+		require.Nil(t, response.Results[3].Response)
+		require.Equal(t, int32(codes.InvalidArgument), response.Results[4].Code)
+		require.Empty(t, response.Results[5].RequestId)
+		require.Equal(t, int32(codes.InvalidArgument), response.Results[5].Code)
+
+		_, err = client.AuthorizeBatch(ctx, &AuthBatch{Items: make([]*AuthItem, maxBatchSize+1)})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		_, client := startServer(t)
+		stream, err := client.AuthorizeStream(ctx)
+		require.Nil(t, err)
+		for i := 1; i <= 3; i++ {
+			require.Nil(t, stream.Send(item(fmt.Sprint(i), "login", "2001:db8::1")))
+		}
+		require.Nil(t, stream.Send(item("4", "", "2001:db8::1")))
+		require.Nil(t, stream.CloseSend())
+
+		results := []*AuthResult{}
+		for {
+			result, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.Nil(t, err)
+			results = append(results, result)
+		}
+		require.Len(t, results, 4)
+		require.Equal(t, "3", results[2].RequestId)
+		require.Equal(t, Reason_LOGIN_LIMIT, results[2].Response.Reason)
+		require.Equal(t, "4", results[3].RequestId)
+		require.Equal(t, int32(codes.InvalidArgument), results[3].Code)
+	})
+
+	t.Run("stream ends on shutdown", func(t *testing.T) {
+		service, client := startServer(t)
+		stream, err := client.AuthorizeStream(ctx)
+		require.Nil(t, err)
+		require.Nil(t, stream.Send(item("1", "login", "192.0.2.1")))
+		result, err := stream.Recv()
+		require.Nil(t, err)
+		require.True(t, result.Response.Ok)
+
+		require.Nil(t, service.ShutDown())
+		_, err = stream.Recv()
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
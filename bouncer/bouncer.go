@@ -5,68 +5,233 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"math"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	sync "sync"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 const defaultConfigPath = "../config/config.json"
 
+const defaultShutdownTimeout = 10 * time.Second
+
+const errorLogInterval = 10 * time.Second
+
+var ErrShutdownTimeout = errors.New("In-flight requests were not drained before the shutdown timeout")
+
 type Service struct {
-	lock        sync.RWMutex
-	bucketBunch map[string]buckets
-	config      ConfigStruct
-	server      *grpc.Server
-	listener    net.Listener
+	lock       sync.RWMutex
+	listLock   sync.Mutex
+	store      BucketStore
+	limiters   map[string]Limiter
+	journal    *listJournal
+	lists      map[string]*subnetTrie
+	listIndex  map[string]map[string]int
+	entries    map[string]map[string]listEntry
+	config     ConfigStruct
+	configPath string
+	fileConfig ConfigStruct
+	reloadLock sync.Mutex
+	remover    *time.Ticker
+	cancel     context.CancelFunc
+	server     *grpc.Server
+	listener   net.Listener
+
+	adminServer   *grpc.Server
+	adminListener net.Listener
+
+	metrics       *metrics
+	metricsServer *http.Server
+	health        *health.Server
+	ready         int32
+	audit         *auditLog
+	hasher        *keyHasher
+	events        eventHub
+	escalations   escalator
+	storeErrors   errorLog
+	auditErrors   errorLog
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+	drain        chan struct{}
+	drainInit    sync.Once
+	drainClose   sync.Once
+
+	shadowRejections shadowCounters
 }
 
 type ConfigStruct struct {
 	ListenerAdress string
+	MetricsAdress  string
 	TimerSec       int64
+	ShardCount     int
 	Limit          map[string]int
+	Algorithm      map[string]string
+	ShadowMode     bool
+	Shadow         map[string]bool
+	Storage        StorageConfig
+	ListsJournal   string
 	Lists          map[string][]net.IPNet
+	Audit          AuditConfig
+	HashSecret     string
+	TLS            TLSConfig
+	Access         AccessConfig
+
+	ShutdownTimeoutSec  int64
+	AdminListenerAdress string
+	DisableLegacyAdmin  bool
+	WatchBufferSize     int
+	Escalation          EscalationConfig
+	IPPrefix            IPPrefixConfig
 }
 
 type buckets map[string]bucketDetail
 
 type bucketDetail struct {
-	MainChan       chan bool
+	Tokens         float64
+	LastRefill     time.Time
 	FlagToDelition bool
 }
 
-func (s *Service) InitService() {
+// InitService serves until SIGTERM or SIGINT and then shuts down gracefully.
+// The listener is opened right after the config is read, so health checks
+// answer while the lists are restored. It returns nil only when every
+// in-flight request was drained and the persisted state was flushed.
+func (s *Service) InitService() error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	s.cancel = cancel
 
-	s.loadConfig()
-	s.initValues()
-	s.initGap(ctx)
-	s.InitRemover(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
 
+	s.loadConfig()
 	lsn, err := net.Listen("tcp", s.config.ListenerAdress)
 	PanicOnErr(err)
 	log.Printf("Starting server on %s", lsn.Addr().String())
 
-	s.listener = lsn
-	s.server = grpc.NewServer()
-	RegisterBouncerServer(s.server, s)
-	err = s.server.Serve(lsn)
-	PanicOnErr(err)
+	var adminLsn net.Listener
+	if s.config.AdminListenerAdress != "" {
+		adminLsn, err = net.Listen("tcp", s.config.AdminListenerAdress)
+		PanicOnErr(err)
+		log.Printf("Starting admin server on %s", adminLsn.Addr().String())
+	}
+
+	PanicOnErr(s.initServer(lsn, adminLsn))
+	served := make(chan error, 2)
+	go func() {
+		served <- s.server.Serve(lsn)
+	}()
+	if adminLsn != nil {
+		go func() {
+			served <- s.adminServer.Serve(adminLsn)
+		}()
+	}
+
+	s.initValues()
+	PanicOnErr(s.initAudit())
+	PanicOnErr(s.restoreLists())
+	s.InitRemover(ctx)
+	s.initReloader(ctx)
+	s.initMetricsServer()
+	s.setReady()
+
+	pending := len(s.grpcServers())
+	select {
+	case err = <-served:
+		pending--
+		if shutdownErr := s.ShutDown(); err == nil {
+			err = shutdownErr
+		}
+	case sig := <-signals:
+		log.Printf("Received %s, draining in-flight requests", sig)
+		err = s.ShutDown()
+	}
+	for ; pending > 0; pending-- {
+		<-served
+	}
+	return err
 }
 
-func (s *Service) ShutDown() {
-	s.server.Stop()
-	s.listener.Close()
+// ShutDown stops accepting new requests and waits for in-flight ones up to
+// ShutdownTimeoutSec, then cancels background jobs and flushes the state.
+// Requests still running after the timeout are cancelled.
+func (s *Service) ShutDown() error {
+	s.shutdownOnce.Do(func() {
+		timeout := time.Duration(s.currentConfig().ShutdownTimeoutSec) * time.Second
+		if timeout <= 0 {
+			timeout = defaultShutdownTimeout
+		}
+
+		s.setDraining()
+		s.events.stop()
+		servers := s.grpcServers()
+		drained := make(chan struct{})
+		go func() {
+			var wg sync.WaitGroup
+			for _, server := range servers {
+				wg.Add(1)
+				go func(server *grpc.Server) {
+					defer wg.Done()
+					server.GracefulStop()
+				}(server)
+			}
+			wg.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-time.After(timeout):
+			for _, server := range servers {
+				server.Stop()
+			}
+			<-drained
+			s.shutdownErr = ErrShutdownTimeout
+		}
+
+		if s.metricsServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			s.metricsServer.Shutdown(ctx)
+			cancel()
+		}
+		if s.cancel != nil {
+			s.cancel()
+		}
+		if err := s.journal.Close(); err != nil && s.shutdownErr == nil {
+			s.shutdownErr = errors.Wrap(err, "Closing list journal")
+		}
+		if err := s.audit.Close(); err != nil && s.shutdownErr == nil {
+			s.shutdownErr = errors.Wrap(err, "Closing audit log")
+		}
+		if s.store != nil {
+			if err := s.store.Close(); err != nil && s.shutdownErr == nil {
+				s.shutdownErr = errors.Wrap(err, "Closing bucket store")
+			}
+		}
+		log.Printf("Server stopped")
+	})
+	return s.shutdownErr
 }
 
 func (s *Service) InitRemover(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(s.config.TimerSec) * time.Second)
+	s.lock.Lock()
+	s.remover = ticker
+	s.lock.Unlock()
 
 	go func() {
 		for {
@@ -76,217 +241,566 @@ func (s *Service) InitRemover(ctx context.Context) {
 				return
 			case <-ticker.C:
 				s.RemoveEmptyBuckets()
+				s.expireListEntries(time.Now())
+				s.escalations.sweep(s.currentConfig().Escalation, time.Now())
 			}
 		}
 	}()
 }
 
-func (s *Service) initGap(ctx context.Context) {
-	for bucketType, limit := range s.config.Limit {
-		tickerDelta := 1000 * int(s.config.TimerSec) / limit
-		ticker := time.NewTicker(time.Duration(tickerDelta) * time.Millisecond)
-
-		go func(bucketType string) {
-			for {
-				select {
-				case <-ctx.Done():
-					ticker.Stop()
-					return
-				case <-ticker.C:
-					s.removeFromBuckets(bucketType)
-				}
-			}
-		}(bucketType)
+func (s *Service) loadConfig() {
+	if s.configPath == "" {
+		s.configPath = os.Getenv("CONFIG_PATH")
 	}
-}
-
-func (s *Service) removeFromBuckets(bucketType string) {
-	for _, bucket := range s.bucketBunch[bucketType] {
-		select {
-		case <-bucket.MainChan:
-		default:
-		}
+	if s.configPath == "" {
+		s.configPath = defaultConfigPath
 	}
+
+	config, err := readConfig(s.configPath)
+	PanicOnErr(err)
+	s.config = config
+	s.fileConfig = config
+	s.fileConfig.Lists = copyLists(config.Lists)
 }
 
-func (s *Service) loadConfig() {
+func readConfig(cfgFile string) (ConfigStruct, error) {
 	config := ConfigStruct{}
-	cfgFile := os.Getenv("CONFIG_PATH")
-	if cfgFile == "" {
-		cfgFile = defaultConfigPath
-	}
-
 	configJSONFile, err := os.Open(cfgFile)
-	PanicOnErr(err)
+	if err != nil {
+		return config, errors.Wrap(err, "Reading config")
+	}
 	configByteValue, err := ioutil.ReadAll(configJSONFile)
-	PanicOnErr(err)
 	configJSONFile.Close()
+	if err != nil {
+		return config, errors.Wrap(err, "Reading config")
+	}
 
-	PanicOnErr(json.Unmarshal(configByteValue, &config))
-	s.config = config
+	if err := json.Unmarshal(configByteValue, &config); err != nil {
+		return config, errors.Wrap(err, "Reading config")
+	}
+	return config, errors.Wrap(config.validate(), "Reading config")
+}
+
+// settings returns the config together with the limiters built from it.
+// Reloading replaces the maps instead of changing them, so the returned
+// copy stays consistent without holding the lock.
+func (s *Service) settings() (ConfigStruct, map[string]Limiter) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.config, s.limiters
 }
 
 func (s *Service) initValues() {
-	s.bucketBunch = map[string]buckets{}
+	if s.metrics == nil {
+		s.metrics = newMetrics(s)
+	}
+	s.initLists()
+	hasher, err := s.newKeyHasher()
+	PanicOnErr(err)
+	s.hasher = hasher
+	store, err := s.newStore()
+	PanicOnErr(err)
+	s.store = store
+	s.limiters = map[string]Limiter{}
 	for k := range s.config.Limit {
-		s.bucketBunch[k] = buckets{}
+		limiter, err := s.newLimiter(k)
+		PanicOnErr(err)
+		s.limiters[k] = limiter
 	}
 }
 
 func (s *Service) RemoveEmptyBuckets() {
-	s.lock.Lock()
-	for bucketType, bucketsByType := range s.bucketBunch {
-		for key, bucket := range bucketsByType {
-			if bucket.FlagToDelition {
-				close(bucket.MainChan)
-				delete(s.bucketBunch[bucketType], key)
-			} else {
-				curBucket := bucketDetail{
-					MainChan:       bucket.MainChan,
-					FlagToDelition: true,
-				}
-				s.bucketBunch[bucketType][key] = curBucket
-			}
-		}
+	deleted, err := s.store.Sweep()
+	if err != nil {
+		s.storeError("sweep", errors.Wrap(err, "Removing empty buckets"))
 	}
-	s.lock.Unlock()
+
+	_, limiters := s.settings()
+	for _, limiter := range limiters {
+		deleted += limiter.Sweep()
+	}
+	s.metrics.observeSweep(deleted)
 }
 
 func (s *Service) RemoveBucket(bucketType string, bucketKey string) {
-	s.lock.Lock()
-	close(s.bucketBunch[bucketType][bucketKey].MainChan)
-	delete(s.bucketBunch[bucketType], bucketKey)
-	s.lock.Unlock()
+	if err := s.store.Remove(bucketType, bucketKey); err != nil {
+		s.storeError("remove", errors.Wrap(err, "Removing bucket"))
+	}
 }
 
-func (s *Service) addToBucket(bucketType string, bucketKey string) (isAlive bool) {
-	s.lock.Lock()
-	curBucket, ok := s.bucketBunch[bucketType][bucketKey]
-	if !ok {
-		curBucket = bucketDetail{
-			MainChan:       make(chan bool, s.config.Limit[bucketType]),
-			FlagToDelition: false,
-		}
+// addToBucket lets the attempt through when the store is unavailable, so
+// a storage outage does not lock every user out, unless the storage is
+// configured to fail closed.
+func (s *Service) addToBucket(bucketType string, bucketKey string) (isAlive bool, stats LimiterStats) {
+	config, _ := s.settings()
+	limit := config.Limit[bucketType]
+	rate := config.leakRate(bucketType)
+	isAlive, curBucket, err := s.store.Take(bucketType, bucketKey, limit, rate, time.Now())
+	if err != nil {
+		s.storeError("take", errors.Wrap(err, "Adding to bucket"))
+		return !config.Storage.FailClosed, LimiterStats{Limit: limit}
 	}
-	if curBucket.FlagToDelition {
-		curBucket.FlagToDelition = false
+	return isAlive, tokenStats(curBucket.Tokens, rate, limit)
+}
+
+func (s *Service) getBucket(bucketType string, bucketKey string) (bucketDetail, bool) {
+	curBucket, ok, err := s.store.Get(bucketType, bucketKey)
+	if err != nil {
+		s.storeError("get", errors.Wrap(err, "Getting bucket"))
+		return bucketDetail{}, false
 	}
-	s.bucketBunch[bucketType][bucketKey] = curBucket
-	s.lock.Unlock()
+	return curBucket, ok
+}
 
-	curBucketChan := curBucket.MainChan
+func (config ConfigStruct) leakRate(bucketType string) float64 {
+	return float64(config.Limit[bucketType]) / float64(config.TimerSec)
+}
 
-	select {
-	case curBucketChan <- true:
-		return true
-	default:
-		return false
+func (s *Service) checkLists(address string) (isAlive bool, needCheck bool) {
+	switch listType, _ := s.matchLists(net.ParseIP(address), time.Now()); listType {
+	case "white":
+		return true, false
+	case "black":
+		return false, false
 	}
+	return false, true
 }
 
-func (s *Service) checkLists(address string) (isAlive bool, needCheck bool) {
-	updatedIP := net.ParseIP(address)
-	needCheck = true
-	s.lock.RLock()
-	defer s.lock.RUnlock()
+type authBucket struct {
+	bucketType string
+	bucketKey  string
+	reason     Reason
+}
 
-	for _, v := range s.config.Lists["white"] {
-		if v.Contains(updatedIP) {
-			isAlive = true
-			needCheck = false
-		}
+func (s *Service) authBuckets(in *AuthRequest, ip net.IP) ([]authBucket, error) {
+	buckets := []authBucket{
+		{bucketType: "login", bucketKey: in.Login, reason: Reason_LOGIN_LIMIT},
+		{bucketType: "password", bucketKey: in.Password, reason: Reason_PASSWORD_LIMIT},
 	}
-
-	if needCheck {
-		for _, v := range s.config.Lists["black"] {
-			if v.Contains(updatedIP) {
-				isAlive = false
-				needCheck = false
-			}
+	for i := range buckets {
+		key, err := s.hasher.hashKey(buckets[i].bucketKey, in.PreHashed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Hashing %s", buckets[i].bucketType)
 		}
+		buckets[i].bucketKey = key
 	}
+	return append(buckets, s.ipBuckets(ip)...), nil
+}
 
-	return isAlive, needCheck
+// ipBuckets returns the bucket of the canonical ip and the bucket of its
+// network when IPPrefix groups its family, none for a nil ip.
+func (s *Service) ipBuckets(ip net.IP) []authBucket {
+	if ip == nil {
+		return nil
+	}
+	buckets := []authBucket{{bucketType: "ip", bucketKey: ip.String(), reason: Reason_IP_LIMIT}}
+	if prefix := s.currentConfig().IPPrefix.prefixOf(ip); prefix != nil {
+		buckets = append(buckets, authBucket{bucketType: "subnet", bucketKey: prefix.String(), reason: Reason_SUBNET_LIMIT})
+	}
+	for i := range buckets {
+		buckets[i].bucketKey, _ = s.hasher.hashKey(buckets[i].bucketKey, false)
+	}
+	return buckets
 }
 
+// Authorization takes an attempt from every bucket even if one of them is
+// already exhausted. The reason reports the first tripped bucket and
+// retry_after the longest wait among the tripped ones. Rejections by
+// shadowed bucket types, or any rejection in the service-wide shadow mode,
+// are only logged and counted.
 func (s *Service) Authorization(ctx context.Context, in *AuthRequest) (*AuthResponse, error) {
-	isAlive, needCheck := s.checkLists(in.Ip)
-	if needCheck {
-		loginAnswer := s.addToBucket("login", in.Login)
-		passwordAnswer := s.addToBucket("password", in.Password)
-		ipAnswer := s.addToBucket("ip", in.Ip)
-		isAlive = loginAnswer && passwordAnswer && ipAnswer
+	if err := validateAuthRequest(in); err != nil {
+		return nil, err
 	}
+	ip, err := canonicalIP(in.Ip)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	buckets, err := s.authBuckets(in, ip)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	response := s.authorize(ip.String(), buckets)
+	s.metrics.observeAuthorization(response)
+	s.auditAuthorization(ctx, ip.String(), buckets, response)
+	if !response.Ok {
+		s.events.publish(&Event{Type: EventType_AUTH_DENIED, Ip: ip.String(), LoginHash: buckets[0].bucketKey, Reason: response.Reason})
+		s.escalate(ctx, ip, response)
+	}
+	return response, nil
+}
+
+func (s *Service) authorize(ip string, buckets []authBucket) *AuthResponse {
+	isAlive, needCheck := s.checkLists(ip)
+	if !needCheck {
+		if isAlive {
+			return &AuthResponse{Ok: true, Reason: Reason_WHITELISTED}
+		}
+		if !s.currentConfig().ShadowMode {
+			return &AuthResponse{Ok: false, Reason: Reason_BLACKLISTED}
+		}
+		s.shadowReject(buckets[0].bucketKey, ip, Reason_BLACKLISTED)
+		return &AuthResponse{Ok: true, Reason: Reason_OK}
+	}
+
+	config, limiters := s.settings()
 
-	return &AuthResponse{Ok: isAlive}, nil
+	response := &AuthResponse{Ok: true, Reason: Reason_OK, Remaining: math.MaxInt64}
+	shadowReason := Reason_OK
+	var retryAfter time.Duration
+	for _, bucket := range buckets {
+		limiter, ok := limiters[bucket.bucketType]
+		if !ok {
+			continue
+		}
+		bucketAnswer, stats := limiter.Allow(bucket.bucketKey)
+		if !bucketAnswer && config.isShadowed(bucket.bucketType) {
+			if shadowReason == Reason_OK {
+				shadowReason = bucket.reason
+			}
+		} else if !bucketAnswer {
+			if response.Ok {
+				response.Ok = false
+				response.Reason = bucket.reason
+			}
+			if stats.RetryAfter > retryAfter {
+				retryAfter = stats.RetryAfter
+			}
+		}
+		if remaining := int64(stats.Remaining()); remaining < response.Remaining {
+			response.Remaining = remaining
+		}
+	}
+	if shadowReason != Reason_OK {
+		s.shadowReject(buckets[0].bucketKey, ip, shadowReason)
+	}
+	if !response.Ok {
+		response.RetryAfter = durationpb.New(retryAfter)
+	}
+
+	return response
 }
 
+// DropBucket resets the buckets of the login and of the ip, either may be
+// left empty. It fails with codes.NotFound when none of them holds attempts.
+// Every call is audited, the failed ones with their error.
 func (s *Service) DropBucket(ctx context.Context, in *DropBucketParams) (*emptypb.Empty, error) {
-	s.RemoveBucket("ip", in.Ip)
-	s.RemoveBucket("login", in.Login)
+	loginKey, address, err := s.dropBuckets(in)
+	s.auditDropBucket(ctx, address, loginKey, err)
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(&Event{Type: EventType_BUCKET_DROPPED, Ip: address, LoginHash: loginKey})
 
 	return &emptypb.Empty{}, nil
 }
 
+// dropBuckets returns the login bucket key and the canonical ip of the
+// request, or the ip as given when it is not valid.
+func (s *Service) dropBuckets(in *DropBucketParams) (loginKey string, address string, err error) {
+	address = in.Ip
+	if err := validateDropBucket(in); err != nil {
+		return "", address, err
+	}
+	var buckets []authBucket
+	if in.Login != "" {
+		if loginKey, err = s.hasher.hashKey(in.Login, in.PreHashed); err != nil {
+			return "", address, status.Error(codes.InvalidArgument, errors.Wrap(err, "Hashing login").Error())
+		}
+		buckets = append(buckets, authBucket{bucketType: "login", bucketKey: loginKey})
+	}
+	if in.Ip != "" {
+		ip, err := canonicalIP(in.Ip)
+		if err != nil {
+			return loginKey, address, status.Error(codes.InvalidArgument, err.Error())
+		}
+		address = ip.String()
+		buckets = append(buckets, s.ipBuckets(ip)...)
+	}
+
+	_, limiters := s.settings()
+	found := false
+	for _, bucket := range buckets {
+		limiter, ok := limiters[bucket.bucketType]
+		if !ok {
+			continue
+		}
+		if limiter.Stats(bucket.bucketKey).Used > 0 {
+			found = true
+		}
+		limiter.Reset(bucket.bucketKey)
+	}
+	if !found {
+		return loginKey, address, status.Error(codes.NotFound, "no attempts to drop for the login and ip")
+	}
+	return loginKey, address, nil
+}
+
 func (s *Service) AddBlackList(ctx context.Context, in *Subnet) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, s.AddSubnetToList(in.Subnet, "black")
+	err := validateSubnet(in, true)
+	if err == nil {
+		err = listChangeStatus(s.AddListEntry(in.Subnet, "black", in.Ttl.AsDuration(), in.Comment))
+	}
+	s.auditListChange(ctx, auditListAdd, "black", canonicalSubnet(in.Subnet), err)
+	return &emptypb.Empty{}, err
 }
 
 func (s *Service) RemoveBlackList(ctx context.Context, in *Subnet) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, s.RemoveSubnetFromList(in.Subnet, "black")
+	err := validateSubnet(in, false)
+	if err == nil {
+		err = listChangeStatus(s.RemoveSubnetFromList(in.Subnet, "black"))
+	}
+	if err == nil {
+		s.forgetEscalation(in.Subnet)
+	}
+	s.auditListChange(ctx, auditListRemove, "black", canonicalSubnet(in.Subnet), err)
+	return &emptypb.Empty{}, err
 }
 
 func (s *Service) AddWhiteList(ctx context.Context, in *Subnet) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, s.AddSubnetToList(in.Subnet, "white")
+	err := validateSubnet(in, true)
+	if err == nil {
+		err = listChangeStatus(s.AddListEntry(in.Subnet, "white", in.Ttl.AsDuration(), in.Comment))
+	}
+	s.auditListChange(ctx, auditListAdd, "white", canonicalSubnet(in.Subnet), err)
+	return &emptypb.Empty{}, err
 }
 
 func (s *Service) RemoveWhiteList(ctx context.Context, in *Subnet) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, s.RemoveSubnetFromList(in.Subnet, "white")
+	err := validateSubnet(in, false)
+	if err == nil {
+		err = listChangeStatus(s.RemoveSubnetFromList(in.Subnet, "white"))
+	}
+	s.auditListChange(ctx, auditListRemove, "white", canonicalSubnet(in.Subnet), err)
+	return &emptypb.Empty{}, err
 }
 
 func (s *Service) AddSubnetToList(subnet string, listType string) error {
-	oppositeListType := "white"
-	if oppositeListType == listType {
-		oppositeListType = "black"
+	return s.AddListEntry(subnet, listType, 0, "")
+}
+
+// AddListEntry adds the subnet for ttl, or for good when ttl is zero.
+// Adding a subnet that is already in the list replaces its ttl and comment.
+func (s *Service) AddListEntry(subnet string, listType string, ttl time.Duration, comment string) error {
+	if ttl < 0 {
+		return errors.New("Adding subnet to list: ttl must not be negative")
+	}
+	entry := listEntry{Comment: comment}
+	if ttl > 0 {
+		entry.Expires = time.Now().Add(ttl)
 	}
-	err := s.RemoveSubnetFromList(subnet, oppositeListType)
+	_, network, err := net.ParseCIDR(subnet)
 	if err != nil {
 		return errors.Wrap(err, "Adding subnet to list")
 	}
 
-	_, updatedSubnet, err := net.ParseCIDR(subnet)
+	err = s.changeList(*network, func() bool {
+		s.addSubnetLocked(*network, listType, entry)
+		return true
+	}, journalAdd, listType, entry)
 	if err != nil {
 		return errors.Wrap(err, "Adding subnet to list")
 	}
+	s.events.publish(&Event{Type: EventType_LIST_ADDED, List: listType, Subnet: network.String()})
+	return nil
+}
+
+// RemoveSubnetFromList fails with ErrSubnetNotFound when the subnet is not in the list.
+func (s *Service) RemoveSubnetFromList(subnet string, listType string) error {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return errors.Wrap(err, "Removing subnet from list")
+	}
+
+	err = s.changeList(*network, func() bool {
+		return s.removeSubnetLocked(*network, listType)
+	}, journalRemove, listType, listEntry{})
+	if err != nil {
+		return errors.Wrap(err, "Removing subnet from list")
+	}
+	s.events.publish(&Event{Type: EventType_LIST_REMOVED, List: listType, Subnet: network.String()})
+	return nil
+}
+
+// changeList applies the change to the lists and journals it. s.listLock
+// keeps the journal in the order the changes were applied in, while s.lock
+// is not held over the sync so lookups do not wait for the disk. The change
+// is rolled back when the journal write fails. A change reporting false
+// left the lists as they were and fails with ErrSubnetNotFound.
+func (s *Service) changeList(subnet net.IPNet, change func() bool, op string, listType string, entry listEntry) error {
+	s.listLock.Lock()
+	defer s.listLock.Unlock()
 
 	s.lock.Lock()
-	s.config.Lists[listType] = append(s.config.Lists[listType], *updatedSubnet)
+	restore := s.subnetRestorer(subnet)
+	changed := change()
 	s.lock.Unlock()
+	if !changed {
+		return ErrSubnetNotFound
+	}
 
+	if err := s.journal.append(op, subnet.String(), listType, entry); err != nil {
+		s.lock.Lock()
+		restore()
+		s.lock.Unlock()
+		return err
+	}
 	return nil
 }
 
-func (s *Service) RemoveSubnetFromList(subnet string, listType string) error {
-	indexToRemove := -1
+// subnetRestorer returns a func putting the subnet back into the lists as
+// they are now. Both run with s.lock held.
+func (s *Service) subnetRestorer(subnet net.IPNet) func() {
+	key := subnet.String()
+	listed := map[string]bool{}
+	entries := map[string]listEntry{}
+	for _, listType := range []string{"black", "white"} {
+		_, listed[listType] = s.listIndex[listType][key]
+		entries[listType] = s.entries[listType][key]
+	}
+
+	return func() {
+		for listType, wasListed := range listed {
+			if !wasListed {
+				s.removeSubnetLocked(subnet, listType)
+				continue
+			}
+			if s.listFor(listType).Insert(subnet) {
+				s.appendToList(listType, subnet)
+			}
+			s.setListEntry(listType, key, entries[listType])
+		}
+	}
+}
+
+func (s *Service) addSubnet(subnet string, listType string, entry listEntry) error {
 	_, updatedSubnet, err := net.ParseCIDR(subnet)
 	if err != nil {
-		return errors.Wrap(err, "Removing subnet from list")
+		return err
 	}
-	subnet = updatedSubnet.String()
 
-	for i, v := range s.config.Lists[listType] {
-		if v.String() == subnet {
-			indexToRemove = i
-		}
+	s.lock.Lock()
+	s.addSubnetLocked(*updatedSubnet, listType, entry)
+	s.lock.Unlock()
+	return nil
+}
+
+// addSubnetLocked moves the subnet from the opposite list. Callers hold s.lock.
+func (s *Service) addSubnetLocked(subnet net.IPNet, listType string, entry listEntry) {
+	oppositeListType := "white"
+	if oppositeListType == listType {
+		oppositeListType = "black"
 	}
-	if indexToRemove >= 0 {
-		s.lock.Lock()
-		s.config.Lists[listType] = append(s.config.Lists[listType][:indexToRemove], s.config.Lists[listType][indexToRemove+1:]...)
-		s.lock.Unlock()
+	s.removeSubnetLocked(subnet, oppositeListType)
+
+	if s.listFor(listType).Insert(subnet) {
+		s.appendToList(listType, subnet)
+	}
+	s.setListEntry(listType, subnet.String(), entry)
+}
+
+func (s *Service) removeSubnet(subnet string, listType string) error {
+	_, updatedSubnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return err
 	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.removeSubnetLocked(*updatedSubnet, listType)
 	return nil
 }
 
+// removeSubnetLocked reports whether the subnet was in the list. Callers hold s.lock.
+func (s *Service) removeSubnetLocked(subnet net.IPNet, listType string) bool {
+	if !s.listFor(listType).Remove(subnet) {
+		return false
+	}
+	s.setListEntry(listType, subnet.String(), listEntry{})
+	s.removeFromList(listType, subnet)
+	return true
+}
+
+// appendToList and removeFromList keep s.config.Lists in step with the
+// tries. The index of the slice positions makes a removal take constant
+// time, the order of the slice is not kept. Callers hold s.lock.
+func (s *Service) appendToList(listType string, subnet net.IPNet) {
+	if s.listIndex[listType] == nil {
+		s.listIndex[listType] = map[string]int{}
+	}
+	s.listIndex[listType][subnet.String()] = len(s.config.Lists[listType])
+	s.config.Lists[listType] = append(s.config.Lists[listType], subnet)
+}
+
+func (s *Service) removeFromList(listType string, subnet net.IPNet) {
+	index := s.listIndex[listType]
+	key := subnet.String()
+	i, ok := index[key]
+	if !ok {
+		return
+	}
+	subnets := s.config.Lists[listType]
+	last := len(subnets) - 1
+	subnets[i] = subnets[last]
+	index[subnets[i].String()] = i
+	delete(index, key)
+	s.config.Lists[listType] = subnets[:last]
+}
+
+func (s *Service) initLists() {
+	if s.config.Lists == nil {
+		s.config.Lists = map[string][]net.IPNet{}
+	}
+
+	s.lock.Lock()
+	s.lists = map[string]*subnetTrie{}
+	s.listIndex = map[string]map[string]int{}
+	for listType, subnets := range s.config.Lists {
+		s.lists[listType] = newSubnetTrie(nil)
+		s.config.Lists[listType] = nil
+		for _, subnet := range subnets {
+			if s.lists[listType].Insert(subnet) {
+				s.appendToList(listType, subnet)
+			}
+		}
+	}
+	s.lock.Unlock()
+}
+
+// listFor returns the trie of the list, creating it on first use. Callers hold s.lock.
+func (s *Service) listFor(listType string) *subnetTrie {
+	trie, ok := s.lists[listType]
+	if !ok {
+		trie = newSubnetTrie(nil)
+		s.lists[listType] = trie
+	}
+	return trie
+}
+
+// errorLog writes at most one error per errorLogInterval, so an outage
+// does not log a line for every call.
+type errorLog struct {
+	lock       sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+func (l *errorLog) log(err error, now time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.last.IsZero() && now.Sub(l.last) < errorLogInterval {
+		l.suppressed++
+		return
+	}
+	if l.suppressed > 0 {
+		log.Printf("%v, %d more errors since the last one logged", err, l.suppressed)
+	} else {
+		log.Print(err)
+	}
+	l.last = now
+	l.suppressed = 0
+}
+
 func PanicOnErr(err error) {
 	if err != nil {
 		log.Fatal(err)
@@ -13,7 +13,9 @@ import (
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -25,6 +27,122 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Reason int32
+
+const (
+	Reason_OK             Reason = 0
+	Reason_WHITELISTED    Reason = 1
+	Reason_BLACKLISTED    Reason = 2
+	Reason_LOGIN_LIMIT    Reason = 3
+	Reason_PASSWORD_LIMIT Reason = 4
+	Reason_IP_LIMIT       Reason = 5
+	Reason_SUBNET_LIMIT   Reason = 6
+)
+
+// Enum value maps for Reason.
+var (
+	Reason_name = map[int32]string{
+		0: "OK",
+		1: "WHITELISTED",
+		2: "BLACKLISTED",
+		3: "LOGIN_LIMIT",
+		4: "PASSWORD_LIMIT",
+		5: "IP_LIMIT",
+		6: "SUBNET_LIMIT",
+	}
+	Reason_value = map[string]int32{
+		"OK":             0,
+		"WHITELISTED":    1,
+		"BLACKLISTED":    2,
+		"LOGIN_LIMIT":    3,
+		"PASSWORD_LIMIT": 4,
+		"IP_LIMIT":       5,
+		"SUBNET_LIMIT":   6,
+	}
+)
+
+func (x Reason) Enum() *Reason {
+	p := new(Reason)
+	*p = x
+	return p
+}
+
+func (x Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_bouncer_proto_enumTypes[0].Descriptor()
+}
+
+func (Reason) Type() protoreflect.EnumType {
+	return &file_bouncer_proto_enumTypes[0]
+}
+
+func (x Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Reason.Descriptor instead.
+func (Reason) EnumDescriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_UNSPECIFIED EventType = 0
+	EventType_AUTH_DENIED       EventType = 1
+	EventType_LIST_ADDED        EventType = 2
+	EventType_LIST_REMOVED      EventType = 3
+	EventType_BUCKET_DROPPED    EventType = 4
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_UNSPECIFIED",
+		1: "AUTH_DENIED",
+		2: "LIST_ADDED",
+		3: "LIST_REMOVED",
+		4: "BUCKET_DROPPED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_UNSPECIFIED": 0,
+		"AUTH_DENIED":       1,
+		"LIST_ADDED":        2,
+		"LIST_REMOVED":      3,
+		"BUCKET_DROPPED":    4,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_bouncer_proto_enumTypes[1].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_bouncer_proto_enumTypes[1]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{1}
+}
+
 type AuthRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -33,6 +151,8 @@ type AuthRequest struct {
 	Login    string `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	Ip       string `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Login and password are hex encoded SHA-256 digests of the raw values.
+	PreHashed bool `protobuf:"varint,4,opt,name=pre_hashed,json=preHashed,proto3" json:"pre_hashed,omitempty"`
 }
 
 func (x *AuthRequest) Reset() {
@@ -88,12 +208,24 @@ func (x *AuthRequest) GetIp() string {
 	return ""
 }
 
+func (x *AuthRequest) GetPreHashed() bool {
+	if x != nil {
+		return x.PreHashed
+	}
+	return false
+}
+
 type AuthResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Ok     bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Reason Reason `protobuf:"varint,2,opt,name=reason,proto3,enum=bouncer.Reason" json:"reason,omitempty"`
+	// Attempts left in the most exhausted bucket, zero for listed addresses.
+	Remaining int64 `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// Time until the tripped bucket lets the next attempt through.
+	RetryAfter *durationpb.Duration `protobuf:"bytes,4,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
 }
 
 func (x *AuthResponse) Reset() {
@@ -135,6 +267,27 @@ func (x *AuthResponse) GetOk() bool {
 	return false
 }
 
+func (x *AuthResponse) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_OK
+}
+
+func (x *AuthResponse) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *AuthResponse) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
 type DropBucketParams struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -142,6 +295,8 @@ type DropBucketParams struct {
 
 	Login string `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
 	Ip    string `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Login is a hex encoded SHA-256 digest of the raw value.
+	PreHashed bool `protobuf:"varint,3,opt,name=pre_hashed,json=preHashed,proto3" json:"pre_hashed,omitempty"`
 }
 
 func (x *DropBucketParams) Reset() {
@@ -190,12 +345,24 @@ func (x *DropBucketParams) GetIp() string {
 	return ""
 }
 
+func (x *DropBucketParams) GetPreHashed() bool {
+	if x != nil {
+		return x.PreHashed
+	}
+	return false
+}
+
 type Subnet struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Subnet string `protobuf:"bytes,1,opt,name=subnet,proto3" json:"subnet,omitempty"`
+	// Time the entry stays in the list, it is permanent when unset.
+	// Listings report the time left.
+	Ttl *durationpb.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Why the entry was added.
+	Comment string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
 }
 
 func (x *Subnet) Reset() {
@@ -237,423 +404,2269 @@ func (x *Subnet) GetSubnet() string {
 	return ""
 }
 
-var File_bouncer_proto protoreflect.FileDescriptor
+func (x *Subnet) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
 
-var file_bouncer_proto_rawDesc = []byte{
-	0x0a, 0x0d, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4f, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x1e, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x22, 0x38, 0x0a, 0x10, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75,
-	0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f,
-	0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
-	0x22, 0x20, 0x0a, 0x06, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75,
-	0x62, 0x6e, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e,
-	0x65, 0x74, 0x32, 0xfe, 0x02, 0x0a, 0x07, 0x42, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x12, 0x3e,
-	0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x14, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e,
-	0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x41,
-	0x0a, 0x0a, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x62,
-	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65,
-	0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
-	0x00, 0x12, 0x39, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73,
-	0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e,
-	0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0f,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12,
-	0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x41, 0x64,
-	0x64, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75,
-	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x57,
-	0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63,
-	0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Subnet) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
 }
 
-var (
-	file_bouncer_proto_rawDescOnce sync.Once
-	file_bouncer_proto_rawDescData = file_bouncer_proto_rawDesc
-)
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_bouncer_proto_rawDescGZIP() []byte {
-	file_bouncer_proto_rawDescOnce.Do(func() {
-		file_bouncer_proto_rawDescData = protoimpl.X.CompressGZIP(file_bouncer_proto_rawDescData)
-	})
-	return file_bouncer_proto_rawDescData
+	// Subnets per page, 100 by default and 1000 at most.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Continues the listing after the page with this next_page_token.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 }
 
-var file_bouncer_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_bouncer_proto_goTypes = []interface{}{
-	(*AuthRequest)(nil),      // 0: bouncer.AuthRequest
-	(*AuthResponse)(nil),     // 1: bouncer.AuthResponse
-	(*DropBucketParams)(nil), // 2: bouncer.DropBucketParams
-	(*Subnet)(nil),           // 3: bouncer.Subnet
-	(*emptypb.Empty)(nil),    // 4: google.protobuf.Empty
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_bouncer_proto_depIdxs = []int32{
-	0, // 0: bouncer.Bouncer.Authorization:input_type -> bouncer.AuthRequest
-	2, // 1: bouncer.Bouncer.DropBucket:input_type -> bouncer.DropBucketParams
-	3, // 2: bouncer.Bouncer.AddBlackList:input_type -> bouncer.Subnet
-	3, // 3: bouncer.Bouncer.RemoveBlackList:input_type -> bouncer.Subnet
-	3, // 4: bouncer.Bouncer.AddWhiteList:input_type -> bouncer.Subnet
-	3, // 5: bouncer.Bouncer.RemoveWhiteList:input_type -> bouncer.Subnet
-	1, // 6: bouncer.Bouncer.Authorization:output_type -> bouncer.AuthResponse
-	4, // 7: bouncer.Bouncer.DropBucket:output_type -> google.protobuf.Empty
-	4, // 8: bouncer.Bouncer.AddBlackList:output_type -> google.protobuf.Empty
-	4, // 9: bouncer.Bouncer.RemoveBlackList:output_type -> google.protobuf.Empty
-	4, // 10: bouncer.Bouncer.AddWhiteList:output_type -> google.protobuf.Empty
-	4, // 11: bouncer.Bouncer.RemoveWhiteList:output_type -> google.protobuf.Empty
-	6, // [6:12] is the sub-list for method output_type
-	0, // [0:6] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_bouncer_proto_init() }
-func file_bouncer_proto_init() {
-	if File_bouncer_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_bouncer_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bouncer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bouncer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DropBucketParams); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bouncer_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Subnet); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_bouncer_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
-			NumExtensions: 0,
-			NumServices:   1,
-		},
-		GoTypes:           file_bouncer_proto_goTypes,
-		DependencyIndexes: file_bouncer_proto_depIdxs,
-		MessageInfos:      file_bouncer_proto_msgTypes,
-	}.Build()
-	File_bouncer_proto = out.File
-	file_bouncer_proto_rawDesc = nil
-	file_bouncer_proto_goTypes = nil
-	file_bouncer_proto_depIdxs = nil
+	return mi.MessageOf(x)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{4}
+}
 
-// BouncerClient is the client API for Bouncer service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BouncerClient interface {
-	Authorization(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error)
-	DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+func (x *ListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
 }
 
-type bouncerClient struct {
-	cc grpc.ClientConnInterface
+func (x *ListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
-func NewBouncerClient(cc grpc.ClientConnInterface) BouncerClient {
-	return &bouncerClient{cc}
+type SubnetPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subnets []*Subnet `protobuf:"bytes,1,rep,name=subnets,proto3" json:"subnets,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 }
 
-func (c *bouncerClient) Authorization(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
-	out := new(AuthResponse)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/Authorization", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *SubnetPage) Reset() {
+	*x = SubnetPage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return out, nil
 }
 
-func (c *bouncerClient) DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/DropBucket", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *SubnetPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubnetPage) ProtoMessage() {}
+
+func (x *SubnetPage) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return out, nil
+	return mi.MessageOf(x)
 }
 
-func (c *bouncerClient) AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/AddBlackList", in, out, opts...)
-	if err != nil {
-		return nil, err
+// Deprecated: Use SubnetPage.ProtoReflect.Descriptor instead.
+func (*SubnetPage) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubnetPage) GetSubnets() []*Subnet {
+	if x != nil {
+		return x.Subnets
 	}
-	return out, nil
+	return nil
 }
 
-func (c *bouncerClient) RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (x *SubnetPage) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CheckIPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+func (x *CheckIPRequest) Reset() {
+	*x = CheckIPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckIPRequest) ProtoMessage() {}
+
+func (x *CheckIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckIPRequest.ProtoReflect.Descriptor instead.
+func (*CheckIPRequest) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckIPRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type CheckIPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// White or black, empty when the address is in no list.
+	List string `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	// The most specific entry of the list that contains the address.
+	Subnet *Subnet `protobuf:"bytes,2,opt,name=subnet,proto3" json:"subnet,omitempty"`
+	// WHITELISTED or BLACKLISTED when buckets are skipped for the address,
+	// OK otherwise. The white list wins when the address is in both lists.
+	Reason Reason `protobuf:"varint,3,opt,name=reason,proto3,enum=bouncer.Reason" json:"reason,omitempty"`
+}
+
+func (x *CheckIPResponse) Reset() {
+	*x = CheckIPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckIPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckIPResponse) ProtoMessage() {}
+
+func (x *CheckIPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckIPResponse.ProtoReflect.Descriptor instead.
+func (*CheckIPResponse) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CheckIPResponse) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *CheckIPResponse) GetSubnet() *Subnet {
+	if x != nil {
+		return x.Subnet
+	}
+	return nil
+}
+
+func (x *CheckIPResponse) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_OK
+}
+
+type BucketStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Buckets are looked up for the non-empty fields only.
+	Login    string `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Ip       string `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Login and password are hex encoded SHA-256 digests of the raw values.
+	PreHashed bool `protobuf:"varint,4,opt,name=pre_hashed,json=preHashed,proto3" json:"pre_hashed,omitempty"`
+}
+
+func (x *BucketStateRequest) Reset() {
+	*x = BucketStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BucketStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketStateRequest) ProtoMessage() {}
+
+func (x *BucketStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketStateRequest.ProtoReflect.Descriptor instead.
+func (*BucketStateRequest) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BucketStateRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *BucketStateRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *BucketStateRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *BucketStateRequest) GetPreHashed() bool {
+	if x != nil {
+		return x.PreHashed
+	}
+	return false
+}
+
+type BucketState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Login, password, ip or subnet.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Attempts taken from the bucket and not yet leaked out.
+	Used     int64 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Capacity int64 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// Time until the bucket lets the next attempt through, zero if it does now.
+	RetryAfter *durationpb.Duration `protobuf:"bytes,4,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	// The bucket is removed on the next sweep unless it is used before.
+	FlagToDelition bool `protobuf:"varint,5,opt,name=flag_to_delition,json=flagToDelition,proto3" json:"flag_to_delition,omitempty"`
+}
+
+func (x *BucketState) Reset() {
+	*x = BucketState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BucketState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketState) ProtoMessage() {}
+
+func (x *BucketState) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketState.ProtoReflect.Descriptor instead.
+func (*BucketState) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BucketState) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *BucketState) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *BucketState) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *BucketState) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *BucketState) GetFlagToDelition() bool {
+	if x != nil {
+		return x.FlagToDelition
+	}
+	return false
+}
+
+type BucketStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Buckets []*BucketState `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+}
+
+func (x *BucketStateResponse) Reset() {
+	*x = BucketStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BucketStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketStateResponse) ProtoMessage() {}
+
+func (x *BucketStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketStateResponse.ProtoReflect.Descriptor instead.
+func (*BucketStateResponse) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BucketStateResponse) GetBuckets() []*BucketState {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Event types to watch, all of them when empty.
+	Types []EventType `protobuf:"varint,1,rep,packed,name=types,proto3,enum=bouncer.EventType" json:"types,omitempty"`
+	// Only events for addresses and subnets within this subnet or address.
+	IpPrefix string `protobuf:"bytes,2,opt,name=ip_prefix,json=ipPrefix,proto3" json:"ip_prefix,omitempty"`
+	// Only authorization and bucket events for this login.
+	Login string `protobuf:"bytes,3,opt,name=login,proto3" json:"login,omitempty"`
+	// Login is a hex encoded SHA-256 digest of the raw value.
+	PreHashed bool `protobuf:"varint,4,opt,name=pre_hashed,json=preHashed,proto3" json:"pre_hashed,omitempty"`
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchRequest) GetTypes() []EventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetIpPrefix() string {
+	if x != nil {
+		return x.IpPrefix
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetPreHashed() bool {
+	if x != nil {
+		return x.PreHashed
+	}
+	return false
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=bouncer.EventType" json:"type,omitempty"`
+	Time *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Ip   string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Bucket key of the login, raw logins are never sent.
+	LoginHash string `protobuf:"bytes,4,opt,name=login_hash,json=loginHash,proto3" json:"login_hash,omitempty"`
+	Reason    Reason `protobuf:"varint,5,opt,name=reason,proto3,enum=bouncer.Reason" json:"reason,omitempty"`
+	List      string `protobuf:"bytes,6,opt,name=list,proto3" json:"list,omitempty"`
+	Subnet    string `protobuf:"bytes,7,opt,name=subnet,proto3" json:"subnet,omitempty"`
+	// Events skipped since the previous one because the subscriber
+	// did not keep up.
+	Dropped uint64 `protobuf:"varint,8,opt,name=dropped,proto3" json:"dropped,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Event) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_UNSPECIFIED
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Event) GetLoginHash() string {
+	if x != nil {
+		return x.LoginHash
+	}
+	return ""
+}
+
+func (x *Event) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_OK
+}
+
+func (x *Event) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *Event) GetSubnet() string {
+	if x != nil {
+		return x.Subnet
+	}
+	return ""
+}
+
+func (x *Event) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+type AuthItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Echoed in the result of the item.
+	RequestId string       `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Request   *AuthRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (x *AuthItem) Reset() {
+	*x = AuthItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthItem) ProtoMessage() {}
+
+func (x *AuthItem) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthItem.ProtoReflect.Descriptor instead.
+func (*AuthItem) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AuthItem) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuthItem) GetRequest() *AuthRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type AuthResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Unset when the item was rejected with an error.
+	Response *AuthResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	// gRPC status code and message of an item that could not be evaluated.
+	Code  int32  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *AuthResult) Reset() {
+	*x = AuthResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthResult) ProtoMessage() {}
+
+func (x *AuthResult) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthResult.ProtoReflect.Descriptor instead.
+func (*AuthResult) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AuthResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuthResult) GetResponse() *AuthResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *AuthResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AuthResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AuthBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 1000 items at most.
+	Items []*AuthItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *AuthBatch) Reset() {
+	*x = AuthBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthBatch) ProtoMessage() {}
+
+func (x *AuthBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthBatch.ProtoReflect.Descriptor instead.
+func (*AuthBatch) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AuthBatch) GetItems() []*AuthItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type AuthBatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// In the order of the items.
+	Results []*AuthResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *AuthBatchResult) Reset() {
+	*x = AuthBatchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bouncer_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthBatchResult) ProtoMessage() {}
+
+func (x *AuthBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_bouncer_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthBatchResult.ProtoReflect.Descriptor instead.
+func (*AuthBatchResult) Descriptor() ([]byte, []int) {
+	return file_bouncer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AuthBatchResult) GetResults() []*AuthResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_bouncer_proto protoreflect.FileDescriptor
+
+var file_bouncer_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6e, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x65,
+	0x48, 0x61, 0x73, 0x68, 0x65, 0x64, 0x22, 0xa1, 0x01, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x72, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x3a,
+	0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22, 0x57, 0x0a, 0x10, 0x44, 0x72,
+	0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x6f, 0x67, 0x69, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x65, 0x48, 0x61, 0x73,
+	0x68, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x06, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74,
+	0x74, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x49, 0x0a, 0x0b,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x5f, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x6e, 0x65,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72,
+	0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50,
+	0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x20, 0x0a, 0x0e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x77, 0x0a, 0x0f, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x73,
+	0x74, 0x12, 0x27, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e,
+	0x65, 0x74, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x62, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x22, 0x75, 0x0a, 0x12, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67,
+	0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x72, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x70, 0x72, 0x65, 0x48, 0x61, 0x73, 0x68, 0x65, 0x64, 0x22, 0xb7, 0x01, 0x0a, 0x0b, 0x42,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x3a,
+	0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x6c,
+	0x61, 0x67, 0x5f, 0x74, 0x6f, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x66, 0x6c, 0x61, 0x67, 0x54, 0x6f, 0x44, 0x65, 0x6c, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x45, 0x0a, 0x13, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x62,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x0c,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x05,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x62, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x70, 0x5f, 0x70, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x70, 0x50, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70,
+	0x72, 0x65, 0x48, 0x61, 0x73, 0x68, 0x65, 0x64, 0x22, 0xfd, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x12, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f,
+	0x67, 0x69, 0x6e, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e,
+	0x63, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x22, 0x59, 0x0a, 0x08, 0x41, 0x75, 0x74, 0x68,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41,
+	0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x88, 0x01, 0x0a, 0x0a, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x64, 0x12, 0x31, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75,
+	0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x34,
+	0x0a, 0x09, 0x41, 0x75, 0x74, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x22, 0x40, 0x0a, 0x0f, 0x41, 0x75, 0x74, 0x68, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x2a, 0x77, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x57, 0x48, 0x49, 0x54,
+	0x45, 0x4c, 0x49, 0x53, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x4c, 0x41,
+	0x43, 0x4b, 0x4c, 0x49, 0x53, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x4f,
+	0x47, 0x49, 0x4e, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x50,
+	0x41, 0x53, 0x53, 0x57, 0x4f, 0x52, 0x44, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x04, 0x12,
+	0x0c, 0x0a, 0x08, 0x49, 0x50, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x05, 0x12, 0x10, 0x0a,
+	0x0c, 0x53, 0x55, 0x42, 0x4e, 0x45, 0x54, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x06, 0x2a,
+	0x69, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x11,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x44, 0x45, 0x4e, 0x49,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x41, 0x44, 0x44,
+	0x45, 0x44, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x52, 0x45, 0x4d,
+	0x4f, 0x56, 0x45, 0x44, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x42, 0x55, 0x43, 0x4b, 0x45, 0x54,
+	0x5f, 0x44, 0x52, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x04, 0x32, 0x90, 0x04, 0x0a, 0x07, 0x42,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x1a, 0x18, 0x2e, 0x62,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0f, 0x41, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x11, 0x2e, 0x62, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x1a, 0x13,
+	0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x44, 0x0a, 0x0a, 0x44, 0x72, 0x6f,
+	0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x72, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12,
+	0x3c, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12, 0x3f, 0x0a,
+	0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12, 0x3c,
+	0x0a, 0x0c, 0x41, 0x64, 0x64, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f,
+	0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12, 0x3f, 0x0a, 0x0f,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x03, 0x88, 0x02, 0x01, 0x32, 0x86, 0x05,
+	0x0a, 0x0c, 0x42, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x41,
+	0x0a, 0x0a, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x62,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x39, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e,
+	0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0f,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x41, 0x64,
+	0x64, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x57,
+	0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x61, 0x63, 0x6b,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x62, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x50, 0x61, 0x67, 0x65, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x68, 0x69, 0x74, 0x65,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x62, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x50, 0x61, 0x67, 0x65, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x07, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x50, 0x12, 0x17,
+	0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x50,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x72, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x32, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x15, 0x2e, 0x62, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bouncer_proto_rawDescOnce sync.Once
+	file_bouncer_proto_rawDescData = file_bouncer_proto_rawDesc
+)
+
+func file_bouncer_proto_rawDescGZIP() []byte {
+	file_bouncer_proto_rawDescOnce.Do(func() {
+		file_bouncer_proto_rawDescData = protoimpl.X.CompressGZIP(file_bouncer_proto_rawDescData)
+	})
+	return file_bouncer_proto_rawDescData
+}
+
+var file_bouncer_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_bouncer_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_bouncer_proto_goTypes = []interface{}{
+	(Reason)(0),                   // 0: bouncer.Reason
+	(EventType)(0),                // 1: bouncer.EventType
+	(*AuthRequest)(nil),           // 2: bouncer.AuthRequest
+	(*AuthResponse)(nil),          // 3: bouncer.AuthResponse
+	(*DropBucketParams)(nil),      // 4: bouncer.DropBucketParams
+	(*Subnet)(nil),                // 5: bouncer.Subnet
+	(*ListRequest)(nil),           // 6: bouncer.ListRequest
+	(*SubnetPage)(nil),            // 7: bouncer.SubnetPage
+	(*CheckIPRequest)(nil),        // 8: bouncer.CheckIPRequest
+	(*CheckIPResponse)(nil),       // 9: bouncer.CheckIPResponse
+	(*BucketStateRequest)(nil),    // 10: bouncer.BucketStateRequest
+	(*BucketState)(nil),           // 11: bouncer.BucketState
+	(*BucketStateResponse)(nil),   // 12: bouncer.BucketStateResponse
+	(*WatchRequest)(nil),          // 13: bouncer.WatchRequest
+	(*Event)(nil),                 // 14: bouncer.Event
+	(*AuthItem)(nil),              // 15: bouncer.AuthItem
+	(*AuthResult)(nil),            // 16: bouncer.AuthResult
+	(*AuthBatch)(nil),             // 17: bouncer.AuthBatch
+	(*AuthBatchResult)(nil),       // 18: bouncer.AuthBatchResult
+	(*durationpb.Duration)(nil),   // 19: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 21: google.protobuf.Empty
+}
+var file_bouncer_proto_depIdxs = []int32{
+	0,  // 0: bouncer.AuthResponse.reason:type_name -> bouncer.Reason
+	19, // 1: bouncer.AuthResponse.retry_after:type_name -> google.protobuf.Duration
+	19, // 2: bouncer.Subnet.ttl:type_name -> google.protobuf.Duration
+	5,  // 3: bouncer.SubnetPage.subnets:type_name -> bouncer.Subnet
+	5,  // 4: bouncer.CheckIPResponse.subnet:type_name -> bouncer.Subnet
+	0,  // 5: bouncer.CheckIPResponse.reason:type_name -> bouncer.Reason
+	19, // 6: bouncer.BucketState.retry_after:type_name -> google.protobuf.Duration
+	11, // 7: bouncer.BucketStateResponse.buckets:type_name -> bouncer.BucketState
+	1,  // 8: bouncer.WatchRequest.types:type_name -> bouncer.EventType
+	1,  // 9: bouncer.Event.type:type_name -> bouncer.EventType
+	20, // 10: bouncer.Event.time:type_name -> google.protobuf.Timestamp
+	0,  // 11: bouncer.Event.reason:type_name -> bouncer.Reason
+	2,  // 12: bouncer.AuthItem.request:type_name -> bouncer.AuthRequest
+	3,  // 13: bouncer.AuthResult.response:type_name -> bouncer.AuthResponse
+	15, // 14: bouncer.AuthBatch.items:type_name -> bouncer.AuthItem
+	16, // 15: bouncer.AuthBatchResult.results:type_name -> bouncer.AuthResult
+	2,  // 16: bouncer.Bouncer.Authorization:input_type -> bouncer.AuthRequest
+	17, // 17: bouncer.Bouncer.AuthorizeBatch:input_type -> bouncer.AuthBatch
+	15, // 18: bouncer.Bouncer.AuthorizeStream:input_type -> bouncer.AuthItem
+	4,  // 19: bouncer.Bouncer.DropBucket:input_type -> bouncer.DropBucketParams
+	5,  // 20: bouncer.Bouncer.AddBlackList:input_type -> bouncer.Subnet
+	5,  // 21: bouncer.Bouncer.RemoveBlackList:input_type -> bouncer.Subnet
+	5,  // 22: bouncer.Bouncer.AddWhiteList:input_type -> bouncer.Subnet
+	5,  // 23: bouncer.Bouncer.RemoveWhiteList:input_type -> bouncer.Subnet
+	4,  // 24: bouncer.BouncerAdmin.DropBucket:input_type -> bouncer.DropBucketParams
+	5,  // 25: bouncer.BouncerAdmin.AddBlackList:input_type -> bouncer.Subnet
+	5,  // 26: bouncer.BouncerAdmin.RemoveBlackList:input_type -> bouncer.Subnet
+	5,  // 27: bouncer.BouncerAdmin.AddWhiteList:input_type -> bouncer.Subnet
+	5,  // 28: bouncer.BouncerAdmin.RemoveWhiteList:input_type -> bouncer.Subnet
+	6,  // 29: bouncer.BouncerAdmin.ListBlackList:input_type -> bouncer.ListRequest
+	6,  // 30: bouncer.BouncerAdmin.ListWhiteList:input_type -> bouncer.ListRequest
+	8,  // 31: bouncer.BouncerAdmin.CheckIP:input_type -> bouncer.CheckIPRequest
+	10, // 32: bouncer.BouncerAdmin.GetBucketState:input_type -> bouncer.BucketStateRequest
+	13, // 33: bouncer.BouncerAdmin.Watch:input_type -> bouncer.WatchRequest
+	3,  // 34: bouncer.Bouncer.Authorization:output_type -> bouncer.AuthResponse
+	18, // 35: bouncer.Bouncer.AuthorizeBatch:output_type -> bouncer.AuthBatchResult
+	16, // 36: bouncer.Bouncer.AuthorizeStream:output_type -> bouncer.AuthResult
+	21, // 37: bouncer.Bouncer.DropBucket:output_type -> google.protobuf.Empty
+	21, // 38: bouncer.Bouncer.AddBlackList:output_type -> google.protobuf.Empty
+	21, // 39: bouncer.Bouncer.RemoveBlackList:output_type -> google.protobuf.Empty
+	21, // 40: bouncer.Bouncer.AddWhiteList:output_type -> google.protobuf.Empty
+	21, // 41: bouncer.Bouncer.RemoveWhiteList:output_type -> google.protobuf.Empty
+	21, // 42: bouncer.BouncerAdmin.DropBucket:output_type -> google.protobuf.Empty
+	21, // 43: bouncer.BouncerAdmin.AddBlackList:output_type -> google.protobuf.Empty
+	21, // 44: bouncer.BouncerAdmin.RemoveBlackList:output_type -> google.protobuf.Empty
+	21, // 45: bouncer.BouncerAdmin.AddWhiteList:output_type -> google.protobuf.Empty
+	21, // 46: bouncer.BouncerAdmin.RemoveWhiteList:output_type -> google.protobuf.Empty
+	7,  // 47: bouncer.BouncerAdmin.ListBlackList:output_type -> bouncer.SubnetPage
+	7,  // 48: bouncer.BouncerAdmin.ListWhiteList:output_type -> bouncer.SubnetPage
+	9,  // 49: bouncer.BouncerAdmin.CheckIP:output_type -> bouncer.CheckIPResponse
+	12, // 50: bouncer.BouncerAdmin.GetBucketState:output_type -> bouncer.BucketStateResponse
+	14, // 51: bouncer.BouncerAdmin.Watch:output_type -> bouncer.Event
+	34, // [34:52] is the sub-list for method output_type
+	16, // [16:34] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_bouncer_proto_init() }
+func file_bouncer_proto_init() {
+	if File_bouncer_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_bouncer_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropBucketParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subnet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubnetPage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckIPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckIPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BucketStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BucketState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BucketStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bouncer_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthBatchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bouncer_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_bouncer_proto_goTypes,
+		DependencyIndexes: file_bouncer_proto_depIdxs,
+		EnumInfos:         file_bouncer_proto_enumTypes,
+		MessageInfos:      file_bouncer_proto_msgTypes,
+	}.Build()
+	File_bouncer_proto = out.File
+	file_bouncer_proto_rawDesc = nil
+	file_bouncer_proto_goTypes = nil
+	file_bouncer_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BouncerClient is the client API for Bouncer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BouncerClient interface {
+	Authorization(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Evaluates every item like Authorization, an invalid item fails alone.
+	AuthorizeBatch(ctx context.Context, in *AuthBatch, opts ...grpc.CallOption) (*AuthBatchResult, error)
+	// Answers each item as it arrives, in the order of the items.
+	AuthorizeStream(ctx context.Context, opts ...grpc.CallOption) (Bouncer_AuthorizeStreamClient, error)
+	// Deprecated: Do not use.
+	// Admin RPCs are kept for compatibility, use BouncerAdmin instead.
+	// DisableLegacyAdmin rejects them with UNIMPLEMENTED.
+	DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type bouncerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBouncerClient(cc grpc.ClientConnInterface) BouncerClient {
+	return &bouncerClient{cc}
+}
+
+func (c *bouncerClient) Authorization(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
+	out := new(AuthResponse)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/Authorization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerClient) AuthorizeBatch(ctx context.Context, in *AuthBatch, opts ...grpc.CallOption) (*AuthBatchResult, error) {
+	out := new(AuthBatchResult)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/AuthorizeBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerClient) AuthorizeStream(ctx context.Context, opts ...grpc.CallOption) (Bouncer_AuthorizeStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Bouncer_serviceDesc.Streams[0], "/bouncer.Bouncer/AuthorizeStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bouncerAuthorizeStreamClient{stream}
+	return x, nil
+}
+
+type Bouncer_AuthorizeStreamClient interface {
+	Send(*AuthItem) error
+	Recv() (*AuthResult, error)
+	grpc.ClientStream
+}
+
+type bouncerAuthorizeStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *bouncerAuthorizeStreamClient) Send(m *AuthItem) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bouncerAuthorizeStreamClient) Recv() (*AuthResult, error) {
+	m := new(AuthResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Deprecated: Do not use.
+func (c *bouncerClient) DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/DropBucket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Deprecated: Do not use.
+func (c *bouncerClient) AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/AddBlackList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Deprecated: Do not use.
+func (c *bouncerClient) RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/RemoveBlackList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Deprecated: Do not use.
+func (c *bouncerClient) AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/AddWhiteList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Deprecated: Do not use.
+func (c *bouncerClient) RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/RemoveWhiteList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BouncerServer is the server API for Bouncer service.
+type BouncerServer interface {
+	Authorization(context.Context, *AuthRequest) (*AuthResponse, error)
+	// Evaluates every item like Authorization, an invalid item fails alone.
+	AuthorizeBatch(context.Context, *AuthBatch) (*AuthBatchResult, error)
+	// Answers each item as it arrives, in the order of the items.
+	AuthorizeStream(Bouncer_AuthorizeStreamServer) error
+	// Deprecated: Do not use.
+	// Admin RPCs are kept for compatibility, use BouncerAdmin instead.
+	// DisableLegacyAdmin rejects them with UNIMPLEMENTED.
+	DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error)
+	// Deprecated: Do not use.
+	RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error)
+}
+
+// UnimplementedBouncerServer can be embedded to have forward compatible implementations.
+type UnimplementedBouncerServer struct {
+}
+
+func (*UnimplementedBouncerServer) Authorization(context.Context, *AuthRequest) (*AuthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorization not implemented")
+}
+func (*UnimplementedBouncerServer) AuthorizeBatch(context.Context, *AuthBatch) (*AuthBatchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorizeBatch not implemented")
+}
+func (*UnimplementedBouncerServer) AuthorizeStream(Bouncer_AuthorizeStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method AuthorizeStream not implemented")
+}
+func (*UnimplementedBouncerServer) DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropBucket not implemented")
+}
+func (*UnimplementedBouncerServer) AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBlackList not implemented")
+}
+func (*UnimplementedBouncerServer) RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBlackList not implemented")
+}
+func (*UnimplementedBouncerServer) AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWhiteList not implemented")
+}
+func (*UnimplementedBouncerServer) RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWhiteList not implemented")
+}
+
+func RegisterBouncerServer(s *grpc.Server, srv BouncerServer) {
+	s.RegisterService(&_Bouncer_serviceDesc, srv)
+}
+
+func _Bouncer_Authorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).Authorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/Authorization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).Authorization(ctx, req.(*AuthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_AuthorizeBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).AuthorizeBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/AuthorizeBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).AuthorizeBatch(ctx, req.(*AuthBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_AuthorizeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BouncerServer).AuthorizeStream(&bouncerAuthorizeStreamServer{stream})
+}
+
+type Bouncer_AuthorizeStreamServer interface {
+	Send(*AuthResult) error
+	Recv() (*AuthItem, error)
+	grpc.ServerStream
+}
+
+type bouncerAuthorizeStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *bouncerAuthorizeStreamServer) Send(m *AuthResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bouncerAuthorizeStreamServer) Recv() (*AuthItem, error) {
+	m := new(AuthItem)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Bouncer_DropBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropBucketParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).DropBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/DropBucket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).DropBucket(ctx, req.(*DropBucketParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_AddBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subnet)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).AddBlackList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/AddBlackList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).AddBlackList(ctx, req.(*Subnet))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_RemoveBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subnet)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).RemoveBlackList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/RemoveBlackList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).RemoveBlackList(ctx, req.(*Subnet))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_AddWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subnet)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).AddWhiteList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/AddWhiteList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).AddWhiteList(ctx, req.(*Subnet))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bouncer_RemoveWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subnet)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerServer).RemoveWhiteList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.Bouncer/RemoveWhiteList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerServer).RemoveWhiteList(ctx, req.(*Subnet))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Bouncer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "bouncer.Bouncer",
+	HandlerType: (*BouncerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Authorization",
+			Handler:    _Bouncer_Authorization_Handler,
+		},
+		{
+			MethodName: "AuthorizeBatch",
+			Handler:    _Bouncer_AuthorizeBatch_Handler,
+		},
+		{
+			MethodName: "DropBucket",
+			Handler:    _Bouncer_DropBucket_Handler,
+		},
+		{
+			MethodName: "AddBlackList",
+			Handler:    _Bouncer_AddBlackList_Handler,
+		},
+		{
+			MethodName: "RemoveBlackList",
+			Handler:    _Bouncer_RemoveBlackList_Handler,
+		},
+		{
+			MethodName: "AddWhiteList",
+			Handler:    _Bouncer_AddWhiteList_Handler,
+		},
+		{
+			MethodName: "RemoveWhiteList",
+			Handler:    _Bouncer_RemoveWhiteList_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AuthorizeStream",
+			Handler:       _Bouncer_AuthorizeStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "bouncer.proto",
+}
+
+// BouncerAdminClient is the client API for BouncerAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BouncerAdminClient interface {
+	DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListBlackList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (BouncerAdmin_ListBlackListClient, error)
+	ListWhiteList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (BouncerAdmin_ListWhiteListClient, error)
+	CheckIP(ctx context.Context, in *CheckIPRequest, opts ...grpc.CallOption) (*CheckIPResponse, error)
+	GetBucketState(ctx context.Context, in *BucketStateRequest, opts ...grpc.CallOption) (*BucketStateResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (BouncerAdmin_WatchClient, error)
+}
+
+type bouncerAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBouncerAdminClient(cc grpc.ClientConnInterface) BouncerAdminClient {
+	return &bouncerAdminClient{cc}
+}
+
+func (c *bouncerAdminClient) DropBucket(ctx context.Context, in *DropBucketParams, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/RemoveBlackList", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/DropBucket", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bouncerClient) AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *bouncerAdminClient) AddBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/AddWhiteList", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/AddBlackList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bouncerClient) RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *bouncerAdminClient) RemoveBlackList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/bouncer.Bouncer/RemoveWhiteList", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/RemoveBlackList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// BouncerServer is the server API for Bouncer service.
-type BouncerServer interface {
-	Authorization(context.Context, *AuthRequest) (*AuthResponse, error)
+func (c *bouncerAdminClient) AddWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/AddWhiteList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerAdminClient) RemoveWhiteList(ctx context.Context, in *Subnet, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/RemoveWhiteList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerAdminClient) ListBlackList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (BouncerAdmin_ListBlackListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BouncerAdmin_serviceDesc.Streams[0], "/bouncer.BouncerAdmin/ListBlackList", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bouncerAdminListBlackListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BouncerAdmin_ListBlackListClient interface {
+	Recv() (*SubnetPage, error)
+	grpc.ClientStream
+}
+
+type bouncerAdminListBlackListClient struct {
+	grpc.ClientStream
+}
+
+func (x *bouncerAdminListBlackListClient) Recv() (*SubnetPage, error) {
+	m := new(SubnetPage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bouncerAdminClient) ListWhiteList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (BouncerAdmin_ListWhiteListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BouncerAdmin_serviceDesc.Streams[1], "/bouncer.BouncerAdmin/ListWhiteList", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bouncerAdminListWhiteListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BouncerAdmin_ListWhiteListClient interface {
+	Recv() (*SubnetPage, error)
+	grpc.ClientStream
+}
+
+type bouncerAdminListWhiteListClient struct {
+	grpc.ClientStream
+}
+
+func (x *bouncerAdminListWhiteListClient) Recv() (*SubnetPage, error) {
+	m := new(SubnetPage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bouncerAdminClient) CheckIP(ctx context.Context, in *CheckIPRequest, opts ...grpc.CallOption) (*CheckIPResponse, error) {
+	out := new(CheckIPResponse)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/CheckIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerAdminClient) GetBucketState(ctx context.Context, in *BucketStateRequest, opts ...grpc.CallOption) (*BucketStateResponse, error) {
+	out := new(BucketStateResponse)
+	err := c.cc.Invoke(ctx, "/bouncer.BouncerAdmin/GetBucketState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bouncerAdminClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (BouncerAdmin_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BouncerAdmin_serviceDesc.Streams[2], "/bouncer.BouncerAdmin/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bouncerAdminWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BouncerAdmin_WatchClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type bouncerAdminWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *bouncerAdminWatchClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BouncerAdminServer is the server API for BouncerAdmin service.
+type BouncerAdminServer interface {
 	DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error)
 	AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error)
 	RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error)
 	AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error)
 	RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error)
+	ListBlackList(*ListRequest, BouncerAdmin_ListBlackListServer) error
+	ListWhiteList(*ListRequest, BouncerAdmin_ListWhiteListServer) error
+	CheckIP(context.Context, *CheckIPRequest) (*CheckIPResponse, error)
+	GetBucketState(context.Context, *BucketStateRequest) (*BucketStateResponse, error)
+	Watch(*WatchRequest, BouncerAdmin_WatchServer) error
 }
 
-// UnimplementedBouncerServer can be embedded to have forward compatible implementations.
-type UnimplementedBouncerServer struct {
+// UnimplementedBouncerAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedBouncerAdminServer struct {
 }
 
-func (*UnimplementedBouncerServer) Authorization(context.Context, *AuthRequest) (*AuthResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Authorization not implemented")
-}
-func (*UnimplementedBouncerServer) DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error) {
+func (*UnimplementedBouncerAdminServer) DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DropBucket not implemented")
 }
-func (*UnimplementedBouncerServer) AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+func (*UnimplementedBouncerAdminServer) AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddBlackList not implemented")
 }
-func (*UnimplementedBouncerServer) RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+func (*UnimplementedBouncerAdminServer) RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveBlackList not implemented")
 }
-func (*UnimplementedBouncerServer) AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+func (*UnimplementedBouncerAdminServer) AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddWhiteList not implemented")
 }
-func (*UnimplementedBouncerServer) RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+func (*UnimplementedBouncerAdminServer) RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveWhiteList not implemented")
 }
+func (*UnimplementedBouncerAdminServer) ListBlackList(*ListRequest, BouncerAdmin_ListBlackListServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBlackList not implemented")
+}
+func (*UnimplementedBouncerAdminServer) ListWhiteList(*ListRequest, BouncerAdmin_ListWhiteListServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListWhiteList not implemented")
+}
+func (*UnimplementedBouncerAdminServer) CheckIP(context.Context, *CheckIPRequest) (*CheckIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckIP not implemented")
+}
+func (*UnimplementedBouncerAdminServer) GetBucketState(context.Context, *BucketStateRequest) (*BucketStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBucketState not implemented")
+}
+func (*UnimplementedBouncerAdminServer) Watch(*WatchRequest, BouncerAdmin_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 
-func RegisterBouncerServer(s *grpc.Server, srv BouncerServer) {
-	s.RegisterService(&_Bouncer_serviceDesc, srv)
+func RegisterBouncerAdminServer(s *grpc.Server, srv BouncerAdminServer) {
+	s.RegisterService(&_BouncerAdmin_serviceDesc, srv)
 }
 
-func _Bouncer_Authorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthRequest)
+func _BouncerAdmin_DropBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropBucketParams)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).Authorization(ctx, in)
+		return srv.(BouncerAdminServer).DropBucket(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/Authorization",
+		FullMethod: "/bouncer.BouncerAdmin/DropBucket",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).Authorization(ctx, req.(*AuthRequest))
+		return srv.(BouncerAdminServer).DropBucket(ctx, req.(*DropBucketParams))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Bouncer_DropBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DropBucketParams)
+func _BouncerAdmin_AddBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subnet)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).DropBucket(ctx, in)
+		return srv.(BouncerAdminServer).AddBlackList(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/DropBucket",
+		FullMethod: "/bouncer.BouncerAdmin/AddBlackList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).DropBucket(ctx, req.(*DropBucketParams))
+		return srv.(BouncerAdminServer).AddBlackList(ctx, req.(*Subnet))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Bouncer_AddBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _BouncerAdmin_RemoveBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Subnet)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).AddBlackList(ctx, in)
+		return srv.(BouncerAdminServer).RemoveBlackList(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/AddBlackList",
+		FullMethod: "/bouncer.BouncerAdmin/RemoveBlackList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).AddBlackList(ctx, req.(*Subnet))
+		return srv.(BouncerAdminServer).RemoveBlackList(ctx, req.(*Subnet))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Bouncer_RemoveBlackList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _BouncerAdmin_AddWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Subnet)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).RemoveBlackList(ctx, in)
+		return srv.(BouncerAdminServer).AddWhiteList(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/RemoveBlackList",
+		FullMethod: "/bouncer.BouncerAdmin/AddWhiteList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).RemoveBlackList(ctx, req.(*Subnet))
+		return srv.(BouncerAdminServer).AddWhiteList(ctx, req.(*Subnet))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Bouncer_AddWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _BouncerAdmin_RemoveWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Subnet)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).AddWhiteList(ctx, in)
+		return srv.(BouncerAdminServer).RemoveWhiteList(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/AddWhiteList",
+		FullMethod: "/bouncer.BouncerAdmin/RemoveWhiteList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).AddWhiteList(ctx, req.(*Subnet))
+		return srv.(BouncerAdminServer).RemoveWhiteList(ctx, req.(*Subnet))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Bouncer_RemoveWhiteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Subnet)
+func _BouncerAdmin_ListBlackList_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BouncerAdminServer).ListBlackList(m, &bouncerAdminListBlackListServer{stream})
+}
+
+type BouncerAdmin_ListBlackListServer interface {
+	Send(*SubnetPage) error
+	grpc.ServerStream
+}
+
+type bouncerAdminListBlackListServer struct {
+	grpc.ServerStream
+}
+
+func (x *bouncerAdminListBlackListServer) Send(m *SubnetPage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BouncerAdmin_ListWhiteList_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BouncerAdminServer).ListWhiteList(m, &bouncerAdminListWhiteListServer{stream})
+}
+
+type BouncerAdmin_ListWhiteListServer interface {
+	Send(*SubnetPage) error
+	grpc.ServerStream
+}
+
+type bouncerAdminListWhiteListServer struct {
+	grpc.ServerStream
+}
+
+func (x *bouncerAdminListWhiteListServer) Send(m *SubnetPage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BouncerAdmin_CheckIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckIPRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BouncerServer).RemoveWhiteList(ctx, in)
+		return srv.(BouncerAdminServer).CheckIP(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bouncer.Bouncer/RemoveWhiteList",
+		FullMethod: "/bouncer.BouncerAdmin/CheckIP",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BouncerServer).RemoveWhiteList(ctx, req.(*Subnet))
+		return srv.(BouncerAdminServer).CheckIP(ctx, req.(*CheckIPRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Bouncer_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "bouncer.Bouncer",
-	HandlerType: (*BouncerServer)(nil),
+func _BouncerAdmin_GetBucketState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BucketStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BouncerAdminServer).GetBucketState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bouncer.BouncerAdmin/GetBucketState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BouncerAdminServer).GetBucketState(ctx, req.(*BucketStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BouncerAdmin_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BouncerAdminServer).Watch(m, &bouncerAdminWatchServer{stream})
+}
+
+type BouncerAdmin_WatchServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type bouncerAdminWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *bouncerAdminWatchServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BouncerAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "bouncer.BouncerAdmin",
+	HandlerType: (*BouncerAdminServer)(nil),
 	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Authorization",
-			Handler:    _Bouncer_Authorization_Handler,
-		},
 		{
 			MethodName: "DropBucket",
-			Handler:    _Bouncer_DropBucket_Handler,
+			Handler:    _BouncerAdmin_DropBucket_Handler,
 		},
 		{
 			MethodName: "AddBlackList",
-			Handler:    _Bouncer_AddBlackList_Handler,
+			Handler:    _BouncerAdmin_AddBlackList_Handler,
 		},
 		{
 			MethodName: "RemoveBlackList",
-			Handler:    _Bouncer_RemoveBlackList_Handler,
+			Handler:    _BouncerAdmin_RemoveBlackList_Handler,
 		},
 		{
 			MethodName: "AddWhiteList",
-			Handler:    _Bouncer_AddWhiteList_Handler,
+			Handler:    _BouncerAdmin_AddWhiteList_Handler,
 		},
 		{
 			MethodName: "RemoveWhiteList",
-			Handler:    _Bouncer_RemoveWhiteList_Handler,
+			Handler:    _BouncerAdmin_RemoveWhiteList_Handler,
+		},
+		{
+			MethodName: "CheckIP",
+			Handler:    _BouncerAdmin_CheckIP_Handler,
+		},
+		{
+			MethodName: "GetBucketState",
+			Handler:    _BouncerAdmin_GetBucketState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBlackList",
+			Handler:       _BouncerAdmin_ListBlackList_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListWhiteList",
+			Handler:       _BouncerAdmin_ListWhiteList_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _BouncerAdmin_Watch_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "bouncer.proto",
 }
@@ -1,7 +1,10 @@
 package bouncer
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"log"
 	"math/rand"
 	"os"
 	"strconv"
@@ -34,8 +37,9 @@ func TestBouncer(t *testing.T) {
 		for i := 0; i <= bouncer.config.Limit["login"]; i++ {
 			bouncer.addToBucket("login", testLogin)
 		}
-		target := bouncer.addToBucket("login", testLogin)
-		require.Equal(t, bouncer.config.Limit["login"], len(bouncer.bucketBunch["login"][testLogin].MainChan))
+		target, _ := bouncer.addToBucket("login", testLogin)
+		curBucket, _ := bouncer.getBucket("login", testLogin)
+		require.Less(t, curBucket.Tokens, float64(1))
 		require.False(t, target)
 	})
 
@@ -45,16 +49,134 @@ func TestBouncer(t *testing.T) {
 			bouncer.addToBucket("login", testLogin)
 		}
 		bouncer.RemoveBucket("login", testLogin)
-		target := bouncer.addToBucket("login", testLogin)
-		require.Equal(t, 1, len(bouncer.bucketBunch["login"][testLogin].MainChan))
+		target, _ := bouncer.addToBucket("login", testLogin)
+		curBucket, _ := bouncer.getBucket("login", testLogin)
+		require.InDelta(t, float64(bouncer.config.Limit["login"]-1), curBucket.Tokens, 0.01)
 		require.True(t, target)
 	})
 
+	t.Run("bucket refill", func(t *testing.T) {
+		bouncer.initValues()
+		for i := 0; i <= bouncer.config.Limit["login"]; i++ {
+			bouncer.addToBucket("login", testLogin)
+		}
+		curBucket, _ := bouncer.getBucket("login", testLogin)
+		curBucket.LastRefill = curBucket.LastRefill.Add(-time.Duration(bouncer.config.TimerSec) * time.Second)
+		bouncer.store.(*memoryStore).set("login", testLogin, curBucket)
+
+		target, _ := bouncer.addToBucket("login", testLogin)
+		require.True(t, target)
+		curBucket, _ = bouncer.getBucket("login", testLogin)
+		require.InDelta(t, float64(bouncer.config.Limit["login"]-1), curBucket.Tokens, 0.01)
+	})
+
+	t.Run("empty buckets removing", func(t *testing.T) {
+		bouncer.initValues()
+		bouncer.addToBucket("login", testLogin)
+		bouncer.RemoveEmptyBuckets()
+		curBucket, ok := bouncer.getBucket("login", testLogin)
+		require.True(t, ok)
+		require.True(t, curBucket.FlagToDelition)
+		bouncer.RemoveEmptyBuckets()
+		_, ok = bouncer.getBucket("login", testLogin)
+		require.False(t, ok)
+	})
+
+	t.Run("sharding", func(t *testing.T) {
+		bouncer.initValues()
+		store := bouncer.store.(*memoryStore)
+		require.Len(t, store.shards, defaultShardCount)
+		require.Same(t, store.shardFor(testLogin), store.shardFor(testLogin))
+
+		for i := 0; i < defaultShardCount*4; i++ {
+			bouncer.addToBucket("login", strconv.Itoa(i))
+		}
+		used := 0
+		for _, shard := range store.shards {
+			if len(shard.bucketBunch["login"]) > 0 {
+				used++
+			}
+		}
+		require.Greater(t, used, 1)
+	})
+
+	t.Run("authorization reasons", func(t *testing.T) {
+		bouncer.initValues()
+		authRequest := &AuthRequest{Login: testLogin, Password: testLogin, Ip: "2001:db8::7"}
+		limit := bouncer.config.Limit["login"]
+		for i := 1; i <= limit; i++ {
+			authResponse, err := bouncer.Authorization(context.Background(), authRequest)
+			require.Nil(t, err)
+			require.True(t, authResponse.Ok)
+			require.Equal(t, Reason_OK, authResponse.Reason)
+			require.Equal(t, int64(limit-i), authResponse.Remaining)
+		}
+
+		authResponse, err := bouncer.Authorization(context.Background(), authRequest)
+		require.Nil(t, err)
+		require.False(t, authResponse.Ok)
+		require.Equal(t, Reason_LOGIN_LIMIT, authResponse.Reason)
+		require.Equal(t, int64(0), authResponse.Remaining)
+		leakInterval := time.Duration(bouncer.config.TimerSec) * time.Second / time.Duration(limit)
+		require.InDelta(t, float64(leakInterval), float64(authResponse.RetryAfter.AsDuration()), float64(time.Second))
+
+		require.Nil(t, bouncer.AddSubnetToList("2001:db8::/64", "white"))
+		authResponse, err = bouncer.Authorization(context.Background(), authRequest)
+		require.Nil(t, err)
+		require.True(t, authResponse.Ok)
+		require.Equal(t, Reason_WHITELISTED, authResponse.Reason)
+
+		require.Nil(t, bouncer.AddSubnetToList("2001:db8::/64", "black"))
+		authResponse, err = bouncer.Authorization(context.Background(), authRequest)
+		require.Nil(t, err)
+		require.False(t, authResponse.Ok)
+		require.Equal(t, Reason_BLACKLISTED, authResponse.Reason)
+		require.Nil(t, bouncer.RemoveSubnetFromList("2001:db8::/64", "black"))
+	})
+
+	t.Run("shadow mode", func(t *testing.T) {
+		bouncer.initValues()
+		defer func() {
+			bouncer.config.ShadowMode = false
+			bouncer.config.Shadow = nil
+		}()
+		authRequest := &AuthRequest{Login: testLogin, Password: testLogin, Ip: "2001:db8::8"}
+		rejected := bouncer.ShadowRejections()[Reason_LOGIN_LIMIT]
+
+		bouncer.config.Shadow = map[string]bool{"login": true}
+		output := &bytes.Buffer{}
+		log.SetOutput(output)
+		for i := 0; i <= bouncer.config.Limit["login"]; i++ {
+			authResponse, err := bouncer.Authorization(context.Background(), authRequest)
+			require.Nil(t, err)
+			require.True(t, authResponse.Ok)
+		}
+		log.SetOutput(os.Stderr)
+		require.Equal(t, rejected+1, bouncer.ShadowRejections()[Reason_LOGIN_LIMIT])
+		loginKey, _ := bouncer.hasher.hashKey(testLogin, false)
+		require.Contains(t, output.String(), loginKey)
+		require.NotContains(t, output.String(), strconv.Quote(testLogin))
+
+		bouncer.config.Shadow = nil
+		authResponse, err := bouncer.Authorization(context.Background(), authRequest)
+		require.Nil(t, err)
+		require.False(t, authResponse.Ok)
+
+		bouncer.config.ShadowMode = true
+		require.Nil(t, bouncer.AddSubnetToList("2001:db8::/64", "black"))
+		authResponse, err = bouncer.Authorization(context.Background(), authRequest)
+		require.Nil(t, err)
+		require.True(t, authResponse.Ok)
+		require.Equal(t, Reason_OK, authResponse.Reason)
+		require.NotZero(t, bouncer.ShadowRejections()[Reason_BLACKLISTED])
+		require.Nil(t, bouncer.RemoveSubnetFromList("2001:db8::/64", "black"))
+	})
+
 	t.Run("whitelist", func(t *testing.T) {
 		bouncer.initValues()
 		target := true
 		for i := 0; i <= bouncer.config.Limit["ip"]; i++ {
-			target = bouncer.addToBucket("ip", testSubnet)
+			target, _ = bouncer.addToBucket("ip", testSubnet)
 		}
 		require.False(t, target)
 
@@ -67,7 +189,7 @@ func TestBouncer(t *testing.T) {
 
 	t.Run("blacklist", func(t *testing.T) {
 		bouncer.initValues()
-		target := bouncer.addToBucket("ip", testSubnet)
+		target, _ := bouncer.addToBucket("ip", testSubnet)
 		require.True(t, target)
 
 		err = bouncer.AddSubnetToList(testSubnet, "black")
@@ -0,0 +1,165 @@
+package bouncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultEscalationForget = 24 * time.Hour
+
+// EscalationConfig bans an IP whose attempts were rejected by a rate limit
+// Trips times within WindowSec, or its whole network when the rejections come
+// from the network bucket. The first ban lasts BanSec and every next one
+// twice as long as the previous, up to MaxBanSec. The number of bans is
+// forgotten after ForgetSec without rejections, 24 hours by default.
+// Escalation is off when Trips is zero.
+type EscalationConfig struct {
+	Trips     int
+	WindowSec int64
+	BanSec    int64
+	MaxBanSec int64
+	ForgetSec int64
+}
+
+type offender struct {
+	trips    []time.Time
+	bans     int
+	lastSeen time.Time
+}
+
+type escalator struct {
+	lock      sync.Mutex
+	offenders map[string]*offender
+}
+
+func (config EscalationConfig) forget() time.Duration {
+	if config.ForgetSec <= 0 {
+		return defaultEscalationForget
+	}
+	return time.Duration(config.ForgetSec) * time.Second
+}
+
+// banDuration doubles the first ban bans times without going over MaxBanSec.
+func (config EscalationConfig) banDuration(bans int) time.Duration {
+	ban := time.Duration(config.BanSec) * time.Second
+	maxBan := time.Duration(config.MaxBanSec) * time.Second
+	for i := 0; i < bans && (maxBan == 0 || ban < maxBan); i++ {
+		ban *= 2
+	}
+	if maxBan > 0 && ban > maxBan {
+		ban = maxBan
+	}
+	return ban
+}
+
+// trip records a rejection of the subnet. It returns the ban to apply and its
+// number, or zero when the subnet has not tripped often enough yet.
+func (e *escalator) trip(subnet string, config EscalationConfig, now time.Time) (time.Duration, int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.offenders == nil {
+		e.offenders = map[string]*offender{}
+	}
+	o, ok := e.offenders[subnet]
+	if !ok || now.Sub(o.lastSeen) > config.forget() {
+		o = &offender{}
+		e.offenders[subnet] = o
+	}
+	if now.After(o.lastSeen) {
+		o.lastSeen = now
+	}
+
+	windowStart := now.Add(-time.Duration(config.WindowSec) * time.Second)
+	trips := o.trips[:0]
+	for _, trip := range o.trips {
+		if trip.After(windowStart) {
+			trips = append(trips, trip)
+		}
+	}
+	o.trips = append(trips, now)
+	if len(o.trips) < config.Trips {
+		return 0, 0
+	}
+
+	ban := config.banDuration(o.bans)
+	o.trips = nil
+	o.bans++
+	o.lastSeen = now.Add(ban)
+	return ban, o.bans
+}
+
+func (e *escalator) forget(subnet string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.offenders, subnet)
+}
+
+// sweep drops the offenders that have nothing left to remember.
+func (e *escalator) sweep(config EscalationConfig, now time.Time) {
+	keep := config.forget()
+	if window := time.Duration(config.WindowSec) * time.Second; window > keep {
+		keep = window
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for subnet, o := range e.offenders {
+		if now.Sub(o.lastSeen) > keep {
+			delete(e.offenders, subnet)
+		}
+	}
+}
+
+func isLimitReason(reason Reason) bool {
+	switch reason {
+	case Reason_LOGIN_LIMIT, Reason_PASSWORD_LIMIT, Reason_IP_LIMIT, Reason_SUBNET_LIMIT:
+		return true
+	}
+	return false
+}
+
+func hostSubnet(ip net.IP) net.IPNet {
+	bits := len(ip) * 8
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
+
+// escalate counts the rate limit rejection of the attempt and blacklists
+// its IP once the escalation policy says so. A rejection by the network
+// bucket counts against the network and blacklists all of it.
+func (s *Service) escalate(ctx context.Context, ip net.IP, response *AuthResponse) {
+	config := s.currentConfig()
+	if config.Escalation.Trips <= 0 || response.Ok || !isLimitReason(response.Reason) {
+		return
+	}
+	subnet := hostSubnet(ip)
+	if response.Reason == Reason_SUBNET_LIMIT {
+		if prefix := config.IPPrefix.prefixOf(ip); prefix != nil {
+			subnet = *prefix
+		}
+	}
+
+	ban, bans := s.escalations.trip(subnet.String(), config.Escalation, time.Now())
+	if ban == 0 {
+		return
+	}
+	err := s.AddListEntry(subnet.String(), "black", ban, fmt.Sprintf("escalation ban %d", bans))
+	if err != nil {
+		log.Printf("Escalating %s: %v", ip, err)
+	}
+	s.auditEscalation(ctx, ip.String(), subnet.String(), ban, bans, err)
+}
+
+// forgetEscalation resets the escalation of the subnet when its ban is
+// lifted through the API, so a mistaken ban does not make the next one longer.
+func (s *Service) forgetEscalation(subnet string) {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return
+	}
+	s.escalations.forget(network.String())
+}
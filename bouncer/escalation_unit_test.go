@@ -0,0 +1,152 @@
+package bouncer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalation(t *testing.T) {
+	config := EscalationConfig{Trips: 3, WindowSec: 60, BanSec: 60, MaxBanSec: 200}
+
+	t.Run("ban durations", func(t *testing.T) {
+		require.Equal(t, time.Minute, config.banDuration(0))
+		require.Equal(t, 2*time.Minute, config.banDuration(1))
+		require.Equal(t, 200*time.Second, config.banDuration(2))
+		require.Equal(t, 200*time.Second, config.banDuration(100))
+		unbounded := EscalationConfig{BanSec: 1}
+		require.Equal(t, 8*time.Second, unbounded.banDuration(3))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		valid := testConfig()
+		valid.Escalation = config
+		require.Nil(t, valid.validate())
+		invalid := valid
+		invalid.Escalation = EscalationConfig{Trips: 3, BanSec: 60}
+		require.Error(t, invalid.validate())
+		invalid.Escalation = EscalationConfig{Trips: 3, WindowSec: 60, BanSec: 60, MaxBanSec: 30}
+		require.Error(t, invalid.validate())
+	})
+
+	t.Run("trips", func(t *testing.T) {
+		e := &escalator{}
+		now := time.Now()
+		ban, _ := e.trip("192.0.2.1/32", config, now)
+		require.Zero(t, ban)
+		ban, _ = e.trip("192.0.2.1/32", config, now.Add(30*time.Second))
+		require.Zero(t, ban)
+		ban, _ = e.trip("192.0.2.1/32", config, now.Add(70*time.Second))
+		require.Zero(t, ban, "the first trip is out of the window")
+		ban, bans := e.trip("192.0.2.1/32", config, now.Add(80*time.Second))
+		require.Equal(t, time.Minute, ban)
+		require.Equal(t, 1, bans)
+
+		now = now.Add(200 * time.Second)
+		for i := 0; i < 3; i++ {
+			ban, bans = e.trip("192.0.2.1/32", config, now)
+		}
+		require.Equal(t, 2*time.Minute, ban)
+		require.Equal(t, 2, bans)
+
+		now = now.Add(2*time.Minute + defaultEscalationForget + time.Second)
+		for i := 0; i < 3; i++ {
+			ban, bans = e.trip("192.0.2.1/32", config, now)
+		}
+		require.Equal(t, time.Minute, ban)
+		require.Equal(t, 1, bans)
+
+		e.sweep(config, now.Add(time.Minute))
+		require.Len(t, e.offenders, 1)
+		e.sweep(config, now.Add(time.Minute+defaultEscalationForget+time.Second))
+		require.Empty(t, e.offenders)
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		auditPath := filepath.Join(t.TempDir(), "audit.log")
+		service := newTestService(t, func(c *ConfigStruct) {
+			c.Limit["login"] = 1
+			c.Escalation = config
+			c.Audit = AuditConfig{Sink: AuditSinkFile, Path: auditPath}
+		})
+		service.initValues()
+		require.Nil(t, service.initAudit())
+		defer service.audit.Close()
+		ctx := context.Background()
+		request := &AuthRequest{Login: "login", Password: "password", Ip: "2001:db8::1"}
+
+		for i := 0; i < 4; i++ {
+			response, err := service.Authorization(ctx, request)
+			require.Nil(t, err)
+			require.Equal(t, i == 0, response.Ok)
+		}
+		response, err := service.Authorization(ctx, request)
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+
+		check, err := service.CheckIP(ctx, &CheckIPRequest{Ip: "2001:db8::1"})
+		require.Nil(t, err)
+		require.Equal(t, "2001:db8::1/128", check.Subnet.Subnet)
+		require.Equal(t, "escalation ban 1", check.Subnet.Comment)
+		require.True(t, check.Subnet.Ttl.AsDuration() > 0)
+
+		_, err = service.RemoveBlackList(ctx, &Subnet{Subnet: "2001:db8::1/128"})
+		require.Nil(t, err)
+		require.Empty(t, service.escalations.offenders)
+		response, err = service.Authorization(ctx, request)
+		require.Nil(t, err)
+		require.Equal(t, Reason_LOGIN_LIMIT, response.Reason)
+
+		content, err := ioutil.ReadFile(auditPath)
+		require.Nil(t, err)
+		var audit, escalations []auditRecord
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			record := auditRecord{}
+			require.Nil(t, json.Unmarshal([]byte(line), &record))
+			audit = append(audit, record)
+			if record.Event == auditEscalation {
+				escalations = append(escalations, record)
+			}
+		}
+		require.Len(t, escalations, 1)
+		require.Equal(t, "2001:db8::1/128", escalations[0].Subnet)
+		require.Equal(t, 1, escalations[0].Ban)
+		require.Equal(t, "1m0s", escalations[0].BanDuration)
+		require.Equal(t, auditListRemove, audit[len(audit)-2].Event)
+	})
+
+	t.Run("network ban", func(t *testing.T) {
+		service := newTestService(t, func(c *ConfigStruct) {
+			c.Limit = map[string]int{"login": 100, "password": 100, "ip": 100, "subnet": 1}
+			c.IPPrefix = IPPrefixConfig{V4: 24}
+			c.Escalation = config
+		})
+		service.initValues()
+		ctx := context.Background()
+
+		for i := 1; i <= 4; i++ {
+			response, err := service.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: net.IPv4(192, 0, 2, byte(i)).String()})
+			require.Nil(t, err)
+			require.Equal(t, i == 1, response.Ok)
+		}
+		response, err := service.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.200"})
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+
+		check, err := service.CheckIP(ctx, &CheckIPRequest{Ip: "192.0.2.200"})
+		require.Nil(t, err)
+		require.Equal(t, "192.0.2.0/24", check.Subnet.Subnet)
+		require.Contains(t, service.escalations.offenders, "192.0.2.0/24")
+
+		_, err = service.RemoveBlackList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Nil(t, err)
+		require.Empty(t, service.escalations.offenders)
+	})
+}
@@ -0,0 +1,63 @@
+package bouncer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const hashSecretEnv = "BOUNCER_HASH_SECRET"
+
+// keyHasher turns logins, passwords and addresses into keyed hashes before
+// they reach a bucket store, so raw values are never kept in memory or Redis.
+// A key is HMAC-SHA256 of the SHA-256 digest of the value, which lets clients
+// send the digest instead of the raw value and still hit the same bucket.
+type keyHasher struct {
+	secret []byte
+}
+
+// newKeyHasher takes the secret from BOUNCER_HASH_SECRET or HashSecret.
+// Without a secret a random one is generated, which is fine for a single
+// replica but not for replicas sharing Redis.
+func (s *Service) newKeyHasher() (*keyHasher, error) {
+	secret := s.config.HashSecret
+	if env := os.Getenv(hashSecretEnv); env != "" {
+		secret = env
+	}
+	if secret != "" {
+		return &keyHasher{secret: []byte(secret)}, nil
+	}
+	if s.config.Storage.Type == StorageRedis {
+		return nil, errors.Errorf("HashSecret or %s must be set for the shared redis storage", hashSecretEnv)
+	}
+
+	random := make([]byte, sha256.Size)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Wrap(err, "Generating hash secret")
+	}
+	log.Printf("HashSecret is not set, bucket keys are hashed with a random secret")
+	return &keyHasher{secret: random}, nil
+}
+
+func (h *keyHasher) hashKey(value string, preHashed bool) (string, error) {
+	var digest []byte
+	if preHashed {
+		decoded, err := hex.DecodeString(value)
+		if err != nil || len(decoded) != sha256.Size {
+			return "", errors.New("Pre-hashed value is not a hex encoded SHA-256 digest")
+		}
+		digest = decoded
+	} else {
+		sum := sha256.Sum256([]byte(value))
+		digest = sum[:]
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(digest)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
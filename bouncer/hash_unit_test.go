@@ -0,0 +1,93 @@
+package bouncer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestKeyHashing(t *testing.T) {
+	const testLogin = "login"
+	const testPassword = "s3cr3t-passw0rd"
+
+	digest := func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:])
+	}
+	newService := func(t *testing.T) *Service {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.Limit["login"] = 2
+			config.HashSecret = "secret"
+		})
+		service.initValues()
+		return service
+	}
+
+	t.Run("keys depend on the secret", func(t *testing.T) {
+		first := &keyHasher{secret: []byte("first")}
+		second := &keyHasher{secret: []byte("second")}
+		firstKey, err := first.hashKey(testPassword, false)
+		require.Nil(t, err)
+		secondKey, err := second.hashKey(testPassword, false)
+		require.Nil(t, err)
+		require.NotEqual(t, firstKey, secondKey)
+		require.NotContains(t, firstKey, testPassword)
+
+		preHashedKey, err := first.hashKey(digest(testPassword), true)
+		require.Nil(t, err)
+		require.Equal(t, firstKey, preHashedKey)
+
+		_, err = first.hashKey(testPassword, true)
+		require.Error(t, err)
+	})
+
+	t.Run("no raw values in the store", func(t *testing.T) {
+		service := newService(t)
+		_, err := service.Authorization(context.Background(), &AuthRequest{Login: testLogin, Password: testPassword, Ip: "198.51.100.1"})
+		require.Nil(t, err)
+
+		for _, shard := range service.store.(*memoryStore).shards {
+			for _, bucketsByType := range shard.bucketBunch {
+				for key := range bucketsByType {
+					require.False(t, strings.Contains(key, testPassword) || strings.Contains(key, testLogin))
+				}
+			}
+		}
+	})
+
+	t.Run("pre-hashed request shares the bucket", func(t *testing.T) {
+		service := newService(t)
+		request := &AuthRequest{Login: testLogin, Password: testPassword, Ip: "198.51.100.1"}
+		response, err := service.Authorization(context.Background(), request)
+		require.Nil(t, err)
+		require.True(t, response.Ok)
+
+		preHashed := &AuthRequest{Login: digest(testLogin), Password: digest(testPassword), Ip: "198.51.100.1", PreHashed: true}
+		response, err = service.Authorization(context.Background(), preHashed)
+		require.Nil(t, err)
+		require.True(t, response.Ok)
+		response, err = service.Authorization(context.Background(), request)
+		require.Nil(t, err)
+		require.Equal(t, Reason_LOGIN_LIMIT, response.Reason)
+
+		_, err = service.DropBucket(context.Background(), &DropBucketParams{Login: digest(testLogin), Ip: "198.51.100.1", PreHashed: true})
+		require.Nil(t, err)
+		response, err = service.Authorization(context.Background(), request)
+		require.Nil(t, err)
+		require.True(t, response.Ok)
+	})
+
+	t.Run("invalid pre-hashed value", func(t *testing.T) {
+		service := newService(t)
+		_, err := service.Authorization(context.Background(), &AuthRequest{Login: testLogin, Password: testPassword, Ip: "198.51.100.1", PreHashed: true})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.DropBucket(context.Background(), &DropBucketParams{Login: testLogin, PreHashed: true})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,194 @@
+package bouncer
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// servedServices are the names health is reported for, "" stands for
+// the whole server.
+var servedServices = []string{"", _Bouncer_serviceDesc.ServiceName, _BouncerAdmin_serviceDesc.ServiceName}
+
+// initServer creates the gRPC server with the health and reflection
+// services. BouncerAdmin gets a server of its own when adminLsn is given,
+// DisableLegacyAdmin then keeps its methods off the Bouncer service too.
+// Health reports NOT_SERVING and Bouncer calls are rejected with
+// codes.Unavailable until setReady is called.
+func (s *Service) initServer(lsn net.Listener, adminLsn net.Listener) error {
+	options := []grpc.ServerOption{}
+	creds, err := s.serverCredentials()
+	if err != nil {
+		return err
+	}
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+
+	if s.metrics == nil {
+		s.metrics = newMetrics(s)
+	}
+	s.health = health.NewServer()
+	for _, name := range servedServices {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	s.listener = lsn
+	s.server = s.newGRPCServer(options)
+	if s.config.DisableLegacyAdmin {
+		RegisterBouncerServer(s.server, bouncerWithoutAdmin{s})
+	} else {
+		RegisterBouncerServer(s.server, s)
+	}
+	if adminLsn == nil {
+		RegisterBouncerAdminServer(s.server, s)
+	} else {
+		s.adminListener = adminLsn
+		s.adminServer = s.newGRPCServer(options)
+		RegisterBouncerAdminServer(s.adminServer, s)
+	}
+	return nil
+}
+
+func (s *Service) newGRPCServer(options []grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(options,
+		grpc.ChainUnaryInterceptor(s.metrics.unaryInterceptor, s.readyUnaryInterceptor, s.accessUnaryInterceptor),
+		grpc.ChainStreamInterceptor(s.metrics.streamInterceptor, s.readyStreamInterceptor, s.accessStreamInterceptor),
+	)...)
+	healthpb.RegisterHealthServer(server, healthServer{Server: s.health, service: s})
+	reflection.Register(server)
+	return server
+}
+
+// bouncerWithoutAdmin serves Bouncer with its deprecated admin methods
+// rejected, so they are only reachable through BouncerAdmin.
+type bouncerWithoutAdmin struct {
+	*Service
+}
+
+var errLegacyAdmin = status.Error(codes.Unimplemented, "admin methods are served by BouncerAdmin only")
+
+func (bouncerWithoutAdmin) DropBucket(context.Context, *DropBucketParams) (*emptypb.Empty, error) {
+	return nil, errLegacyAdmin
+}
+
+func (bouncerWithoutAdmin) AddBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, errLegacyAdmin
+}
+
+func (bouncerWithoutAdmin) RemoveBlackList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, errLegacyAdmin
+}
+
+func (bouncerWithoutAdmin) AddWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, errLegacyAdmin
+}
+
+func (bouncerWithoutAdmin) RemoveWhiteList(context.Context, *Subnet) (*emptypb.Empty, error) {
+	return nil, errLegacyAdmin
+}
+
+// healthServer ends the health Watch streams once the service drains, the
+// stock server keeps them open and GracefulStop would wait on them.
+type healthServer struct {
+	*health.Server
+	service *Service
+}
+
+type healthWatchStream struct {
+	healthpb.Health_WatchServer
+	ctx context.Context
+}
+
+func (s *healthWatchStream) Context() context.Context { return s.ctx }
+
+func (h healthServer) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	result := make(chan error, 1)
+	go func() {
+		result <- h.Server.Watch(in, &healthWatchStream{Health_WatchServer: stream, ctx: ctx})
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-h.service.draining():
+		cancel()
+		<-result
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+}
+
+// grpcServers returns the main server and the admin one if it is separate.
+func (s *Service) grpcServers() []*grpc.Server {
+	if s.adminServer == nil {
+		return []*grpc.Server{s.server}
+	}
+	return []*grpc.Server{s.server, s.adminServer}
+}
+
+func (s *Service) setReady() {
+	atomic.StoreInt32(&s.ready, 1)
+	for _, name := range servedServices {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+}
+
+// setDraining makes health checks fail while in-flight requests are drained,
+// so load balancers stop routing new requests to the instance, and ends the
+// long-lived streams.
+func (s *Service) setDraining() {
+	s.draining()
+	s.drainClose.Do(func() {
+		close(s.drain)
+	})
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+}
+
+// draining is closed once the service starts shutting down.
+func (s *Service) draining() <-chan struct{} {
+	s.drainInit.Do(func() {
+		s.drain = make(chan struct{})
+	})
+	return s.drain
+}
+
+// isBouncerMethod tells the Bouncer and BouncerAdmin methods from the
+// health and reflection ones.
+func isBouncerMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+_Bouncer_serviceDesc.ServiceName+"/") ||
+		strings.HasPrefix(fullMethod, "/"+_BouncerAdmin_serviceDesc.ServiceName+"/")
+}
+
+func (s *Service) checkReady(fullMethod string) error {
+	if atomic.LoadInt32(&s.ready) == 0 && isBouncerMethod(fullMethod) {
+		return status.Error(codes.Unavailable, "service is starting")
+	}
+	return nil
+}
+
+func (s *Service) readyUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkReady(info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Service) readyStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkReady(info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
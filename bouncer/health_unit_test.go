@@ -0,0 +1,69 @@
+package bouncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealth(t *testing.T) {
+	service := newTestService(t, nil)
+	serveTestService(t, service, false)
+	conn := dialTestService(t, service.listener)
+	healthClient := healthpb.NewHealthClient(conn)
+	client := NewBouncerClient(conn)
+	ctx := context.Background()
+
+	checkStatus := func(t *testing.T, expected healthpb.HealthCheckResponse_ServingStatus) {
+		for _, name := range []string{"", "bouncer.Bouncer"} {
+			response, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: name})
+			require.Nil(t, err)
+			require.Equal(t, expected, response.Status)
+		}
+	}
+
+	t.Run("not serving while starting", func(t *testing.T) {
+		checkStatus(t, healthpb.HealthCheckResponse_NOT_SERVING)
+		_, err := client.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("serving when ready", func(t *testing.T) {
+		service.initValues()
+		require.Nil(t, service.restoreLists())
+		service.setReady()
+
+		checkStatus(t, healthpb.HealthCheckResponse_SERVING)
+		response, err := client.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		require.True(t, response.Ok)
+	})
+
+	t.Run("reflection", func(t *testing.T) {
+		stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		require.Nil(t, err)
+		require.Nil(t, stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+		}))
+		response, err := stream.Recv()
+		require.Nil(t, err)
+		services := []string{}
+		for _, service := range response.GetListServicesResponse().Service {
+			services = append(services, service.Name)
+		}
+		require.Contains(t, services, "bouncer.Bouncer")
+		require.Contains(t, services, "grpc.health.v1.Health")
+		require.Nil(t, stream.CloseSend())
+	})
+
+	t.Run("not serving while draining", func(t *testing.T) {
+		service.setDraining()
+		checkStatus(t, healthpb.HealthCheckResponse_NOT_SERVING)
+		require.Nil(t, service.ShutDown())
+	})
+}
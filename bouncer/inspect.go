@@ -0,0 +1,61 @@
+package bouncer
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// GetBucketState reports the buckets an Authorization call with the same
+// fields would take from, without taking anything.
+func (s *Service) GetBucketState(ctx context.Context, in *BucketStateRequest) (*BucketStateResponse, error) {
+	if err := validateBucketStateRequest(in); err != nil {
+		return nil, err
+	}
+	var buckets []authBucket
+	for _, field := range []authBucket{
+		{bucketType: "login", bucketKey: in.Login},
+		{bucketType: "password", bucketKey: in.Password},
+	} {
+		if field.bucketKey == "" {
+			continue
+		}
+		key, err := s.hasher.hashKey(field.bucketKey, in.PreHashed)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrapf(err, "Hashing %s", field.bucketType).Error())
+		}
+		buckets = append(buckets, authBucket{bucketType: field.bucketType, bucketKey: key})
+	}
+	if in.Ip != "" {
+		ip, err := canonicalIP(in.Ip)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		buckets = append(buckets, s.ipBuckets(ip)...)
+	}
+
+	config, limiters := s.settings()
+	response := &BucketStateResponse{}
+	for _, bucket := range buckets {
+		limiter, ok := limiters[bucket.bucketType]
+		if !ok {
+			continue
+		}
+		stats := limiter.Stats(bucket.bucketKey)
+		state := &BucketState{
+			Type:       bucket.bucketType,
+			Used:       int64(stats.Used),
+			Capacity:   int64(stats.Limit),
+			RetryAfter: durationpb.New(stats.RetryAfter),
+		}
+		if algorithmOf(config, bucket.bucketType) == AlgorithmLeaky {
+			curBucket, _ := s.getBucket(bucket.bucketType, bucket.bucketKey)
+			state.FlagToDelition = curBucket.FlagToDelition
+		}
+		response.Buckets = append(response.Buckets, state)
+	}
+	return response, nil
+}
@@ -0,0 +1,59 @@
+package bouncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetBucketState(t *testing.T) {
+	service := newTestService(t, func(config *ConfigStruct) {
+		config.Limit["login"] = 3
+		config.Algorithm = map[string]string{"ip": AlgorithmFixedWindow}
+	})
+	service.initValues()
+	ctx := context.Background()
+	request := &AuthRequest{Login: "login", Password: "password", Ip: "198.51.100.1"}
+	stateRequest := &BucketStateRequest{Login: "login", Password: "password", Ip: "198.51.100.1"}
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Authorization(ctx, request)
+		require.Nil(t, err)
+	}
+	response, err := service.GetBucketState(ctx, stateRequest)
+	require.Nil(t, err)
+	require.Len(t, response.Buckets, 3)
+	login := response.Buckets[0]
+	require.Equal(t, "login", login.Type)
+	require.Equal(t, int64(2), login.Used)
+	require.Equal(t, int64(3), login.Capacity)
+	require.Zero(t, login.RetryAfter.AsDuration())
+	require.False(t, login.FlagToDelition)
+	require.Equal(t, int64(2), response.Buckets[2].Used)
+
+	_, err = service.Authorization(ctx, request)
+	require.Nil(t, err)
+	service.RemoveEmptyBuckets()
+	response, err = service.GetBucketState(ctx, &BucketStateRequest{Login: "login"})
+	require.Nil(t, err)
+	require.Len(t, response.Buckets, 1)
+	require.Equal(t, int64(3), response.Buckets[0].Used)
+	require.True(t, response.Buckets[0].RetryAfter.AsDuration() > 0)
+	require.True(t, response.Buckets[0].FlagToDelition)
+
+	response, err = service.GetBucketState(ctx, &BucketStateRequest{Login: "unknown"})
+	require.Nil(t, err)
+	require.Zero(t, response.Buckets[0].Used)
+
+	_, err = service.GetBucketState(ctx, &BucketStateRequest{Password: "password", PreHashed: true})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err = service.GetBucketState(ctx, &BucketStateRequest{Ip: "198.51.100.1", PreHashed: true})
+	require.Nil(t, err)
+	require.Len(t, response.Buckets, 1)
+	require.Equal(t, "ip", response.Buckets[0].Type)
+	require.Equal(t, int64(3), response.Buckets[0].Used)
+}
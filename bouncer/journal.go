@@ -0,0 +1,221 @@
+package bouncer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	journalAdd    = "add"
+	journalRemove = "remove"
+)
+
+type journalRecord struct {
+	Op       string
+	ListType string
+	Subnet   string
+	Expires  *time.Time `json:",omitempty"`
+	Comment  string     `json:",omitempty"`
+}
+
+func newJournalRecord(op string, subnet string, listType string, entry listEntry) journalRecord {
+	record := journalRecord{Op: op, ListType: listType, Subnet: subnet, Comment: entry.Comment}
+	if !entry.Expires.IsZero() {
+		record.Expires = &entry.Expires
+	}
+	return record
+}
+
+func (r journalRecord) entry() listEntry {
+	entry := listEntry{Comment: r.Comment}
+	if r.Expires != nil {
+		entry.Expires = *r.Expires
+	}
+	return entry
+}
+
+// listJournal is an append-only log of black/white list changes.
+// Every record is synced to disk before the change is acknowledged.
+// A nil *listJournal silently drops records.
+type listJournal struct {
+	lock sync.Mutex
+	path string
+	file *os.File
+}
+
+func openListJournal(path string) (*listJournal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "Opening list journal")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "Opening list journal")
+	}
+	return &listJournal{path: path, file: file}, nil
+}
+
+func (j *listJournal) replay(apply func(record journalRecord) error) error {
+	if _, err := j.file.Seek(0, 0); err != nil {
+		return errors.Wrap(err, "Replaying list journal")
+	}
+
+	scanner := bufio.NewScanner(j.file)
+	for scanner.Scan() {
+		record := journalRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A torn last line after a crash is skipped, the change was never acknowledged.
+			continue
+		}
+		if err := apply(record); err != nil {
+			return errors.Wrap(err, "Replaying list journal")
+		}
+	}
+	return errors.Wrap(scanner.Err(), "Replaying list journal")
+}
+
+func (j *listJournal) append(op string, subnet string, listType string, entry listEntry) error {
+	if j == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(newJournalRecord(op, subnet, listType, entry))
+	if err != nil {
+		return err
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "Writing list journal")
+	}
+	return errors.Wrap(j.file.Sync(), "Writing list journal")
+}
+
+// compact rewrites the journal with the minimal set of records that turn
+// the static lists from config into the current ones. Entries with a ttl
+// or a comment are always written to keep them.
+func (j *listJournal) compact(static map[string][]net.IPNet, current map[string][]net.IPNet, entries map[string]map[string]listEntry) error {
+	buf := []byte{}
+	write := func(op string, subnet string, listType string) error {
+		line, err := json.Marshal(newJournalRecord(op, subnet, listType, entries[listType][subnet]))
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+		return err
+	}
+
+	for listType := range mergeListTypes(static, current) {
+		staticSet := subnetSet(static[listType])
+		currentSet := subnetSet(current[listType])
+		for subnet := range staticSet {
+			if !currentSet[subnet] {
+				if err := write(journalRemove, subnet, listType); err != nil {
+					return err
+				}
+			}
+		}
+		for subnet := range currentSet {
+			if _, ok := entries[listType][subnet]; ok || !staticSet[subnet] {
+				if err := write(journalAdd, subnet, listType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	tmpPath := j.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, buf, 0o644); err != nil {
+		return errors.Wrap(err, "Compacting list journal")
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return errors.Wrap(err, "Compacting list journal")
+	}
+
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "Compacting list journal")
+	}
+	j.file.Close()
+	j.file = file
+	return nil
+}
+
+func (j *listJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.file.Close()
+}
+
+func subnetSet(subnets []net.IPNet) map[string]bool {
+	set := map[string]bool{}
+	for _, subnet := range subnets {
+		set[subnet.String()] = true
+	}
+	return set
+}
+
+func mergeListTypes(lists ...map[string][]net.IPNet) map[string]bool {
+	listTypes := map[string]bool{}
+	for _, list := range lists {
+		for listType := range list {
+			listTypes[listType] = true
+		}
+	}
+	return listTypes
+}
+
+// restoreLists replays list changes made through the API on top of
+// the static lists from config and starts journaling new ones.
+func (s *Service) restoreLists() error {
+	if s.config.ListsJournal == "" {
+		return nil
+	}
+	s.initLists()
+
+	journal, err := openListJournal(s.config.ListsJournal)
+	if err != nil {
+		return err
+	}
+
+	static := copyLists(s.config.Lists)
+
+	now := time.Now()
+	err = journal.replay(func(record journalRecord) error {
+		switch record.Op {
+		case journalAdd:
+			entry := record.entry()
+			if entry.expired(now) {
+				return s.removeSubnet(record.Subnet, record.ListType)
+			}
+			return s.addSubnet(record.Subnet, record.ListType, entry)
+		case journalRemove:
+			return s.removeSubnet(record.Subnet, record.ListType)
+		}
+		return errors.Errorf("Unknown journal operation %q", record.Op)
+	})
+	if err == nil {
+		err = journal.compact(static, s.config.Lists, s.entries)
+	}
+	if err != nil {
+		journal.Close()
+		return err
+	}
+
+	s.journal = journal
+	return nil
+}
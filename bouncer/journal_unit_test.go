@@ -0,0 +1,117 @@
+package bouncer
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListJournal(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "lists.journal")
+	_, staticSubnet, _ := net.ParseCIDR("10.0.0.0/8")
+
+	newService := func() *Service {
+		service := &Service{config: ConfigStruct{
+			ListsJournal: journalPath,
+			Lists: map[string][]net.IPNet{
+				"black": {*staticSubnet},
+				"white": {},
+			},
+		}}
+		require.Nil(t, service.restoreLists())
+		return service
+	}
+
+	t.Run("lists survive restart", func(t *testing.T) {
+		service := newService()
+		require.Nil(t, service.AddSubnetToList("192.168.1.9/24", "black"))
+		require.Nil(t, service.AddSubnetToList("172.16.0.0/12", "white"))
+		require.Nil(t, service.AddSubnetToList("192.168.2.0/24", "black"))
+		require.Nil(t, service.RemoveSubnetFromList("192.168.2.0/24", "black"))
+		require.Nil(t, service.RemoveSubnetFromList("10.0.0.0/8", "black"))
+		require.Nil(t, service.journal.Close())
+		content, err := ioutil.ReadFile(journalPath)
+		require.Nil(t, err)
+		require.Contains(t, string(content), `"192.168.1.0/24"`)
+
+		restored := newService()
+		defer restored.journal.Close()
+		require.Equal(t, subnetSet(service.config.Lists["black"]), subnetSet(restored.config.Lists["black"]))
+		require.Equal(t, subnetSet(service.config.Lists["white"]), subnetSet(restored.config.Lists["white"]))
+		isAlive, needCheck := restored.checkLists("192.168.1.1")
+		require.False(t, isAlive)
+		require.False(t, needCheck)
+		_, needCheck = restored.checkLists("10.1.1.1")
+		require.True(t, needCheck)
+	})
+
+	t.Run("journal compaction", func(t *testing.T) {
+		service := newService()
+		require.Nil(t, service.journal.Close())
+
+		content, err := ioutil.ReadFile(journalPath)
+		require.Nil(t, err)
+		require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 3)
+	})
+
+	t.Run("temporary entries survive restart", func(t *testing.T) {
+		service := newService()
+		require.Nil(t, service.AddListEntry("198.51.100.0/24", "black", time.Hour, "brute force"))
+		require.Nil(t, service.AddListEntry("203.0.113.0/24", "black", time.Millisecond, ""))
+		require.Nil(t, service.AddListEntry("10.0.0.0/8", "black", time.Hour, "static"))
+		require.Nil(t, service.journal.Close())
+		time.Sleep(time.Millisecond)
+
+		restored := newService()
+		require.Equal(t, "brute force", restored.entries["black"]["198.51.100.0/24"].Comment)
+		require.Equal(t, service.entries["black"]["198.51.100.0/24"].Expires.UnixNano(),
+			restored.entries["black"]["198.51.100.0/24"].Expires.UnixNano())
+		_, needCheck := restored.checkLists("203.0.113.1")
+		require.True(t, needCheck)
+		require.Nil(t, restored.journal.Close())
+
+		restored = newService()
+		defer restored.journal.Close()
+		require.Equal(t, "static", restored.entries["black"]["10.0.0.0/8"].Comment)
+		require.Nil(t, restored.RemoveSubnetFromList("198.51.100.0/24", "black"))
+		require.Nil(t, restored.AddSubnetToList("10.0.0.0/8", "black"))
+		require.Empty(t, restored.entries["black"])
+		require.Nil(t, restored.RemoveSubnetFromList("10.0.0.0/8", "black"))
+	})
+
+	t.Run("torn record", func(t *testing.T) {
+		service := newService()
+		_, err := service.journal.file.WriteString(`{"Op":"add","ListType":"bla`)
+		require.Nil(t, err)
+		require.Nil(t, service.journal.Close())
+
+		restored := newService()
+		defer restored.journal.Close()
+		require.Len(t, restored.config.Lists["black"], 1)
+	})
+
+	t.Run("failed write is rolled back", func(t *testing.T) {
+		service := newService()
+		require.Nil(t, service.AddListEntry("192.0.2.0/24", "white", time.Hour, "kept"))
+		require.Nil(t, service.journal.file.Close())
+		white, black := len(service.config.Lists["white"]), len(service.config.Lists["black"])
+
+		require.Error(t, service.AddSubnetToList("192.0.2.0/24", "black"))
+		require.Error(t, service.RemoveSubnetFromList("192.0.2.0/24", "white"))
+		isAlive, needCheck := service.checkLists("192.0.2.1")
+		require.True(t, isAlive)
+		require.False(t, needCheck)
+		require.Equal(t, "kept", service.entries["white"]["192.0.2.0/24"].Comment)
+		require.Len(t, service.config.Lists["white"], white)
+		require.Len(t, service.config.Lists["black"], black)
+
+		require.Error(t, service.AddSubnetToList("198.51.100.0/24", "black"))
+		_, needCheck = service.checkLists("198.51.100.1")
+		require.True(t, needCheck)
+	})
+}
@@ -0,0 +1,549 @@
+package bouncer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	AlgorithmLeaky          = "leaky"
+	AlgorithmToken          = "token"
+	AlgorithmFixedWindow    = "fixed_window"
+	AlgorithmSlidingLog     = "sliding_log"
+	AlgorithmSlidingCounter = "sliding_counter"
+)
+
+// Limiter decides whether one more attempt for the key fits into the limit.
+// Sweep is called by the remover ticker to forget keys that hold no state
+// and returns the number of forgotten keys.
+type Limiter interface {
+	Allow(key string) (bool, LimiterStats)
+	Reset(key string)
+	Stats(key string) LimiterStats
+	Sweep() int
+}
+
+// sizedLimiter is implemented by limiters that keep their keys in memory.
+type sizedLimiter interface {
+	Limiter
+	Len() int
+}
+
+// tunableLimiter is implemented by limiters that can change the limit
+// on config reload keeping the state of their keys.
+type tunableLimiter interface {
+	Limiter
+	tune(limit int, window time.Duration)
+}
+
+// LimiterStats describes the key state. RetryAfter is the time left
+// until the next attempt is allowed, zero if it is allowed right now.
+type LimiterStats struct {
+	Used       int
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (stats LimiterStats) Remaining() int {
+	if stats.Used >= stats.Limit {
+		return 0
+	}
+	return stats.Limit - stats.Used
+}
+
+func knownAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case "", AlgorithmLeaky, AlgorithmToken, AlgorithmFixedWindow, AlgorithmSlidingLog, AlgorithmSlidingCounter:
+		return true
+	}
+	return false
+}
+
+func algorithmOf(config ConfigStruct, bucketType string) string {
+	if algorithm := config.Algorithm[bucketType]; algorithm != "" {
+		return algorithm
+	}
+	return AlgorithmLeaky
+}
+
+// newLimiter builds the limiter from s.config, callers changing the config hold s.lock.
+// Only leaky buckets go through the BucketStore and can be shared between
+// replicas. The other algorithms keep their keys in sharded maps of the
+// process, so config validation rejects them with redis storage.
+func (s *Service) newLimiter(bucketType string) (Limiter, error) {
+	limit := s.config.Limit[bucketType]
+	window := time.Duration(s.config.TimerSec) * time.Second
+
+	var newShard func() memoryLimiter
+	switch algorithmOf(s.config, bucketType) {
+	case AlgorithmLeaky:
+		return &leakyLimiter{service: s, bucketType: bucketType}, nil
+	case AlgorithmToken:
+		newShard = func() memoryLimiter { return newTokenLimiter(limit, window) }
+	case AlgorithmFixedWindow:
+		newShard = func() memoryLimiter { return newFixedWindowLimiter(limit, window) }
+	case AlgorithmSlidingLog:
+		newShard = func() memoryLimiter { return newSlidingLogLimiter(limit, window) }
+	case AlgorithmSlidingCounter:
+		newShard = func() memoryLimiter { return newSlidingCounterLimiter(limit, window) }
+	}
+	if newShard != nil {
+		return newShardedLimiter(s.config.ShardCount, newShard), nil
+	}
+
+	return nil, errors.Errorf("Unknown algorithm %q for bucket type %q", s.config.Algorithm[bucketType], bucketType)
+}
+
+type leakyLimiter struct {
+	service    *Service
+	bucketType string
+}
+
+func (l *leakyLimiter) Allow(key string) (bool, LimiterStats) {
+	return l.service.addToBucket(l.bucketType, key)
+}
+
+func (l *leakyLimiter) Reset(key string) {
+	l.service.RemoveBucket(l.bucketType, key)
+}
+
+func (l *leakyLimiter) Stats(key string) LimiterStats {
+	config, _ := l.service.settings()
+	limit := config.Limit[l.bucketType]
+	rate := config.leakRate(l.bucketType)
+	if bucket, ok := l.service.getBucket(l.bucketType, key); ok {
+		return tokenStats(refillTokens(bucket.Tokens, bucket.LastRefill, time.Now(), rate, limit), rate, limit)
+	}
+	return LimiterStats{Limit: limit}
+}
+
+// Leaky buckets are swept by RemoveEmptyBuckets shard by shard.
+func (l *leakyLimiter) Sweep() int {
+	return 0
+}
+
+// refillTokens returns the amount of tokens accumulated since lastRefill at rate tokens per second.
+func refillTokens(tokens float64, lastRefill time.Time, now time.Time, rate float64, capacity int) float64 {
+	tokens += now.Sub(lastRefill).Seconds() * rate
+	if tokens > float64(capacity) {
+		tokens = float64(capacity)
+	}
+	return tokens
+}
+
+func tokenStats(tokens float64, rate float64, limit int) LimiterStats {
+	stats := LimiterStats{Used: limit - int(tokens), Limit: limit}
+	if tokens < 1 {
+		stats.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return stats
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+type tokenLimiter struct {
+	lock    sync.Mutex
+	limit   int
+	rate    float64
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func newTokenLimiter(limit int, window time.Duration) *tokenLimiter {
+	return &tokenLimiter{
+		limit:   limit,
+		rate:    float64(limit) / window.Seconds(),
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+func (l *tokenLimiter) tune(limit int, window time.Duration) {
+	l.lock.Lock()
+	l.limit = limit
+	l.rate = float64(limit) / window.Seconds()
+	l.lock.Unlock()
+}
+
+func (l *tokenLimiter) refill(bucket *tokenBucket, now time.Time) {
+	bucket.tokens = refillTokens(bucket.tokens, bucket.lastRefill, now, l.rate, l.limit)
+	bucket.lastRefill = now
+}
+
+func (l *tokenLimiter) Allow(key string) (bool, LimiterStats) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.limit), lastRefill: now}
+		l.buckets[key] = bucket
+	}
+	l.refill(bucket, now)
+
+	isAlive := bucket.tokens >= 1
+	if isAlive {
+		bucket.tokens--
+	}
+	return isAlive, tokenStats(bucket.tokens, l.rate, l.limit)
+}
+
+func (l *tokenLimiter) Reset(key string) {
+	l.lock.Lock()
+	delete(l.buckets, key)
+	l.lock.Unlock()
+}
+
+func (l *tokenLimiter) Stats(key string) LimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if bucket, ok := l.buckets[key]; ok {
+		l.refill(bucket, l.now())
+		return tokenStats(bucket.tokens, l.rate, l.limit)
+	}
+	return LimiterStats{Limit: l.limit}
+}
+
+func (l *tokenLimiter) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.buckets)
+}
+
+func (l *tokenLimiter) Sweep() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	before := len(l.buckets)
+	now := l.now()
+	for key, bucket := range l.buckets {
+		l.refill(bucket, now)
+		if bucket.tokens >= float64(l.limit) {
+			delete(l.buckets, key)
+		}
+	}
+	return before - len(l.buckets)
+}
+
+type fixedWindow struct {
+	start time.Time
+	count int
+}
+
+type fixedWindowLimiter struct {
+	lock    sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*fixedWindow
+	now     func() time.Time
+}
+
+func newFixedWindowLimiter(limit int, window time.Duration) *fixedWindowLimiter {
+	return &fixedWindowLimiter{
+		limit:   limit,
+		window:  window,
+		windows: map[string]*fixedWindow{},
+		now:     time.Now,
+	}
+}
+
+func (l *fixedWindowLimiter) tune(limit int, window time.Duration) {
+	l.lock.Lock()
+	l.limit = limit
+	l.window = window
+	l.lock.Unlock()
+}
+
+func (l *fixedWindowLimiter) stats(curWindow *fixedWindow, now time.Time) LimiterStats {
+	stats := LimiterStats{Used: curWindow.count, Limit: l.limit}
+	if curWindow.count >= l.limit {
+		stats.RetryAfter = curWindow.start.Add(l.window).Sub(now)
+	}
+	return stats
+}
+
+func (l *fixedWindowLimiter) Allow(key string) (bool, LimiterStats) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	start := now.Truncate(l.window)
+	curWindow, ok := l.windows[key]
+	if !ok || !curWindow.start.Equal(start) {
+		curWindow = &fixedWindow{start: start}
+		l.windows[key] = curWindow
+	}
+
+	isAlive := curWindow.count < l.limit
+	if isAlive {
+		curWindow.count++
+	}
+	return isAlive, l.stats(curWindow, now)
+}
+
+func (l *fixedWindowLimiter) Reset(key string) {
+	l.lock.Lock()
+	delete(l.windows, key)
+	l.lock.Unlock()
+}
+
+func (l *fixedWindowLimiter) Stats(key string) LimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if curWindow, ok := l.windows[key]; ok && curWindow.start.Equal(now.Truncate(l.window)) {
+		return l.stats(curWindow, now)
+	}
+	return LimiterStats{Limit: l.limit}
+}
+
+func (l *fixedWindowLimiter) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.windows)
+}
+
+func (l *fixedWindowLimiter) Sweep() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	before := len(l.windows)
+	start := l.now().Truncate(l.window)
+	for key, curWindow := range l.windows {
+		if curWindow.start.Before(start) {
+			delete(l.windows, key)
+		}
+	}
+	return before - len(l.windows)
+}
+
+type slidingLogLimiter struct {
+	lock   sync.Mutex
+	limit  int
+	window time.Duration
+	logs   map[string][]time.Time
+	now    func() time.Time
+}
+
+func newSlidingLogLimiter(limit int, window time.Duration) *slidingLogLimiter {
+	return &slidingLogLimiter{
+		limit:  limit,
+		window: window,
+		logs:   map[string][]time.Time{},
+		now:    time.Now,
+	}
+}
+
+func (l *slidingLogLimiter) tune(limit int, window time.Duration) {
+	l.lock.Lock()
+	l.limit = limit
+	l.window = window
+	l.lock.Unlock()
+}
+
+func (l *slidingLogLimiter) trim(key string, now time.Time) []time.Time {
+	log := l.logs[key]
+	border := now.Add(-l.window)
+	i := 0
+	for i < len(log) && !log[i].After(border) {
+		i++
+	}
+	log = log[i:]
+	if len(log) == 0 {
+		delete(l.logs, key)
+		return nil
+	}
+	l.logs[key] = log
+	return log
+}
+
+func (l *slidingLogLimiter) stats(log []time.Time, now time.Time) LimiterStats {
+	stats := LimiterStats{Used: len(log), Limit: l.limit}
+	if len(log) >= l.limit && len(log) > 0 {
+		stats.RetryAfter = log[len(log)-l.limit].Add(l.window).Sub(now)
+	}
+	return stats
+}
+
+func (l *slidingLogLimiter) Allow(key string) (bool, LimiterStats) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	log := l.trim(key, now)
+	isAlive := len(log) < l.limit
+	if isAlive {
+		log = append(log, now)
+		l.logs[key] = log
+	}
+	return isAlive, l.stats(log, now)
+}
+
+func (l *slidingLogLimiter) Reset(key string) {
+	l.lock.Lock()
+	delete(l.logs, key)
+	l.lock.Unlock()
+}
+
+func (l *slidingLogLimiter) Stats(key string) LimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	return l.stats(l.trim(key, now), now)
+}
+
+func (l *slidingLogLimiter) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.logs)
+}
+
+func (l *slidingLogLimiter) Sweep() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	before := len(l.logs)
+	now := l.now()
+	for key := range l.logs {
+		l.trim(key, now)
+	}
+	return before - len(l.logs)
+}
+
+type slidingCounter struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+type slidingCounterLimiter struct {
+	lock     sync.Mutex
+	limit    int
+	window   time.Duration
+	counters map[string]*slidingCounter
+	now      func() time.Time
+}
+
+func newSlidingCounterLimiter(limit int, window time.Duration) *slidingCounterLimiter {
+	return &slidingCounterLimiter{
+		limit:    limit,
+		window:   window,
+		counters: map[string]*slidingCounter{},
+		now:      time.Now,
+	}
+}
+
+func (l *slidingCounterLimiter) tune(limit int, window time.Duration) {
+	l.lock.Lock()
+	l.limit = limit
+	l.window = window
+	l.lock.Unlock()
+}
+
+// shift moves the counter to the window containing now and returns
+// the weighted estimate of attempts made during the last window.
+func (l *slidingCounterLimiter) shift(counter *slidingCounter, now time.Time) float64 {
+	start := now.Truncate(l.window)
+	switch {
+	case start.Equal(counter.start):
+	case start.Sub(counter.start) == l.window:
+		counter.previous, counter.current = counter.current, 0
+		counter.start = start
+	default:
+		counter.previous, counter.current = 0, 0
+		counter.start = start
+	}
+
+	elapsed := float64(now.Sub(start)) / float64(l.window)
+	return float64(counter.previous)*(1-elapsed) + float64(counter.current)
+}
+
+// stats estimates RetryAfter as the time until the weight of the previous
+// windows decays enough to fit one more attempt.
+func (l *slidingCounterLimiter) stats(counter *slidingCounter, estimate float64, now time.Time) LimiterStats {
+	stats := LimiterStats{Used: int(estimate), Limit: l.limit}
+	if estimate+1 <= float64(l.limit) {
+		return stats
+	}
+
+	elapsed := now.Sub(counter.start)
+	previous, current := float64(counter.previous), float64(counter.current)
+	if current+1 > float64(l.limit) {
+		stats.RetryAfter = l.window - elapsed
+		elapsed = 0
+		previous, current = current, 0
+	}
+	if previous > 0 {
+		fraction := 1 - (float64(l.limit)-1-current)/previous
+		if wait := time.Duration(fraction*float64(l.window)) - elapsed; wait > 0 {
+			stats.RetryAfter += wait
+		}
+	}
+	return stats
+}
+
+func (l *slidingCounterLimiter) Allow(key string) (bool, LimiterStats) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	counter, ok := l.counters[key]
+	if !ok {
+		counter = &slidingCounter{start: now.Truncate(l.window)}
+		l.counters[key] = counter
+	}
+
+	estimate := l.shift(counter, now)
+	isAlive := estimate+1 <= float64(l.limit)
+	if isAlive {
+		counter.current++
+		estimate++
+	}
+	return isAlive, l.stats(counter, estimate, now)
+}
+
+func (l *slidingCounterLimiter) Reset(key string) {
+	l.lock.Lock()
+	delete(l.counters, key)
+	l.lock.Unlock()
+}
+
+func (l *slidingCounterLimiter) Stats(key string) LimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if counter, ok := l.counters[key]; ok {
+		return l.stats(counter, l.shift(counter, now), now)
+	}
+	return LimiterStats{Limit: l.limit}
+}
+
+func (l *slidingCounterLimiter) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.counters)
+}
+
+func (l *slidingCounterLimiter) Sweep() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	before := len(l.counters)
+	now := l.now()
+	for key, counter := range l.counters {
+		if l.shift(counter, now) == 0 {
+			delete(l.counters, key)
+		}
+	}
+	return before - len(l.counters)
+}
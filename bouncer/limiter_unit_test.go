@@ -0,0 +1,150 @@
+package bouncer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) add(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+func isAllowed(isAlive bool, _ LimiterStats) bool {
+	return isAlive
+}
+
+func TestLimiters(t *testing.T) {
+	const limit = 10
+	const window = time.Minute
+	const testKey = "login"
+
+	t.Run("token bucket", func(t *testing.T) {
+		clock := &fakeClock{current: time.Unix(0, 0)}
+		limiter := newTokenLimiter(limit, window)
+		limiter.now = clock.now
+
+		for i := 0; i < limit; i++ {
+			require.True(t, isAllowed(limiter.Allow(testKey)))
+		}
+		require.False(t, isAllowed(limiter.Allow(testKey)))
+		require.Equal(t, LimiterStats{Used: limit, Limit: limit, RetryAfter: window / limit}, limiter.Stats(testKey))
+
+		clock.add(window / limit)
+		require.True(t, isAllowed(limiter.Allow(testKey)))
+		require.False(t, isAllowed(limiter.Allow(testKey)))
+
+		limiter.Reset(testKey)
+		require.True(t, isAllowed(limiter.Allow(testKey)))
+
+		clock.add(window)
+		limiter.Sweep()
+		require.Empty(t, limiter.buckets)
+	})
+
+	t.Run("fixed window", func(t *testing.T) {
+		clock := &fakeClock{current: time.Unix(0, 0)}
+		limiter := newFixedWindowLimiter(limit, window)
+		limiter.now = clock.now
+
+		for i := 0; i < limit; i++ {
+			require.True(t, isAllowed(limiter.Allow(testKey)))
+		}
+		require.False(t, isAllowed(limiter.Allow(testKey)))
+
+		clock.add(window - time.Second)
+		isAlive, stats := limiter.Allow(testKey)
+		require.False(t, isAlive)
+		require.Equal(t, time.Second, stats.RetryAfter)
+
+		clock.add(time.Second)
+		require.True(t, isAllowed(limiter.Allow(testKey)))
+		require.Equal(t, LimiterStats{Used: 1, Limit: limit}, limiter.Stats(testKey))
+
+		clock.add(window)
+		limiter.Sweep()
+		require.Empty(t, limiter.windows)
+	})
+
+	t.Run("sliding log", func(t *testing.T) {
+		clock := &fakeClock{current: time.Unix(0, 0)}
+		limiter := newSlidingLogLimiter(limit, window)
+		limiter.now = clock.now
+
+		for i := 0; i < limit; i++ {
+			require.True(t, isAllowed(limiter.Allow(testKey)))
+			clock.add(time.Second)
+		}
+		isAlive, stats := limiter.Allow(testKey)
+		require.False(t, isAlive)
+		require.Equal(t, window-limit*time.Second, stats.RetryAfter)
+
+		clock.add(window - limit*time.Second)
+		require.True(t, isAllowed(limiter.Allow(testKey)))
+		require.False(t, isAllowed(limiter.Allow(testKey)))
+		require.Equal(t, LimiterStats{Used: limit, Limit: limit, RetryAfter: time.Second}, limiter.Stats(testKey))
+
+		clock.add(window)
+		limiter.Sweep()
+		require.Empty(t, limiter.logs)
+	})
+
+	t.Run("sliding counter", func(t *testing.T) {
+		clock := &fakeClock{current: time.Unix(0, 0)}
+		limiter := newSlidingCounterLimiter(limit, window)
+		limiter.now = clock.now
+
+		for i := 0; i < limit; i++ {
+			require.True(t, isAllowed(limiter.Allow(testKey)))
+		}
+		isAlive, stats := limiter.Allow(testKey)
+		require.False(t, isAlive)
+		require.InDelta(t, float64(window+window/10), float64(stats.RetryAfter), float64(time.Millisecond))
+
+		clock.add(window + window/2)
+		for i := 0; i < limit/2; i++ {
+			require.True(t, isAllowed(limiter.Allow(testKey)))
+		}
+		require.False(t, isAllowed(limiter.Allow(testKey)))
+
+		clock.add(2 * window)
+		limiter.Sweep()
+		require.Empty(t, limiter.counters)
+	})
+
+	t.Run("algorithm from config", func(t *testing.T) {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.Limit = map[string]int{"login": limit, "ip": limit}
+			config.Algorithm = map[string]string{"login": AlgorithmSlidingLog}
+		})
+		service.initValues()
+		require.IsType(t, &shardedLimiter{}, service.limiters["login"])
+		require.IsType(t, &slidingLogLimiter{}, service.limiters["login"].(*shardedLimiter).shards[0])
+		require.IsType(t, &leakyLimiter{}, service.limiters["ip"])
+
+		sharded := service.limiters["login"].(*shardedLimiter)
+		require.Len(t, sharded.shards, defaultShardCount)
+		for _, key := range []string{"first", "second", "third"} {
+			require.True(t, isAllowed(sharded.Allow(key)))
+			require.Equal(t, 1, sharded.Stats(key).Used)
+		}
+		require.Equal(t, 3, sharded.Len())
+		sharded.Reset("first")
+		require.Equal(t, 2, sharded.Len())
+		sharded.tune(1, window)
+		require.False(t, isAllowed(sharded.Allow("second")))
+
+		service.config.Algorithm["login"] = "unknown"
+		_, err := service.newLimiter("login")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,233 @@
+package bouncer
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// listEntry keeps what is known about a list subnet besides its address.
+// Entries with a zero Expires never expire.
+type listEntry struct {
+	Expires time.Time
+	Comment string
+}
+
+func (e listEntry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// setListEntry stores the entry, dropping it when there is nothing to keep.
+// Callers hold s.lock.
+func (s *Service) setListEntry(listType string, subnet string, entry listEntry) {
+	if entry == (listEntry{}) {
+		delete(s.entries[listType], subnet)
+		return
+	}
+	if s.entries == nil {
+		s.entries = map[string]map[string]listEntry{}
+	}
+	if s.entries[listType] == nil {
+		s.entries[listType] = map[string]listEntry{}
+	}
+	s.entries[listType][subnet] = entry
+}
+
+// expireListEntries removes the expired subnets. It runs on the remover
+// ticker to free them, lookups and listings skip them as soon as they expire.
+func (s *Service) expireListEntries(now time.Time) {
+	s.listLock.Lock()
+	defer s.listLock.Unlock()
+
+	expired := map[string][]net.IPNet{}
+	s.lock.Lock()
+	for listType, entries := range s.entries {
+		for subnet, entry := range entries {
+			if !entry.expired(now) {
+				continue
+			}
+			_, network, err := net.ParseCIDR(subnet)
+			if err == nil && s.removeSubnetLocked(*network, listType) {
+				expired[listType] = append(expired[listType], *network)
+			}
+		}
+	}
+	s.lock.Unlock()
+
+	for listType, subnets := range expired {
+		for _, subnet := range subnets {
+			s.events.publish(&Event{Type: EventType_LIST_REMOVED, List: listType, Subnet: subnet.String()})
+			if err := s.journal.append(journalRemove, subnet.String(), listType, listEntry{}); err != nil {
+				log.Printf("Expiring list entry: %v", err)
+			}
+		}
+	}
+}
+
+// subnetMessage describes the list subnet with the time it has left.
+// Callers hold s.lock.
+func (s *Service) subnetMessage(listType string, subnet net.IPNet, now time.Time) *Subnet {
+	message := &Subnet{Subnet: subnet.String()}
+	if entry, ok := s.entries[listType][message.Subnet]; ok {
+		message.Comment = entry.Comment
+		if !entry.Expires.IsZero() && entry.Expires.After(now) {
+			message.Ttl = durationpb.New(entry.Expires.Sub(now))
+		}
+	}
+	return message
+}
+
+// unexpired tells whether the subnet has not expired in the list yet.
+// Callers hold s.lock.
+func (s *Service) unexpired(listType string, now time.Time) func(*net.IPNet) bool {
+	entries := s.entries[listType]
+	if len(entries) == 0 {
+		return nil
+	}
+	return func(subnet *net.IPNet) bool {
+		return !entries[subnet.String()].expired(now)
+	}
+}
+
+// matchLists returns the list and its most specific unexpired entry that
+// contain the address. The white list is looked up first.
+func (s *Service) matchLists(ip net.IP, now time.Time) (string, *net.IPNet) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, listType := range []string{"white", "black"} {
+		if subnet, ok := s.lists[listType].LookupFunc(ip, s.unexpired(listType, now)); ok {
+			return listType, subnet
+		}
+	}
+	return "", nil
+}
+
+func (s *Service) CheckIP(ctx context.Context, in *CheckIPRequest) (*CheckIPResponse, error) {
+	if err := checkField("ip", in.Ip, true, maxAddressLength); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(in.Ip)
+	if ip == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid IP address %q", in.Ip)
+	}
+
+	response := &CheckIPResponse{Reason: Reason_OK}
+	now := time.Now()
+	listType, subnet := s.matchLists(ip, now)
+	if subnet == nil {
+		return response, nil
+	}
+	response.List = listType
+	s.lock.RLock()
+	response.Subnet = s.subnetMessage(listType, *subnet, now)
+	s.lock.RUnlock()
+	if listType == "white" {
+		response.Reason = Reason_WHITELISTED
+	} else {
+		response.Reason = Reason_BLACKLISTED
+	}
+	return response, nil
+}
+
+func (s *Service) ListBlackList(in *ListRequest, stream BouncerAdmin_ListBlackListServer) error {
+	return s.listSubnets(in, "black", stream)
+}
+
+func (s *Service) ListWhiteList(in *ListRequest, stream BouncerAdmin_ListWhiteListServer) error {
+	return s.listSubnets(in, "white", stream)
+}
+
+// listSubnets streams the list sorted by address and prefix length, page by
+// page. The page token is the last subnet of the previous page, so changes
+// made to the list between calls do not shift the pages.
+func (s *Service) listSubnets(in *ListRequest, listType string, stream grpc.ServerStream) error {
+	pageSize := int(in.PageSize)
+	if pageSize < 0 || pageSize > maxPageSize {
+		return status.Errorf(codes.InvalidArgument, "page size must be between 0 and %d", maxPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	if err := checkField("page token", in.PageToken, false, maxAddressLength); err != nil {
+		return err
+	}
+	var after *net.IPNet
+	if in.PageToken != "" {
+		_, subnet, err := net.ParseCIDR(in.PageToken)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid page token %q", in.PageToken)
+		}
+		after = subnet
+	}
+
+	now := time.Now()
+	subnets := s.sortedList(listType, now)
+	start := 0
+	if after != nil {
+		start = sort.Search(len(subnets), func(i int) bool {
+			return compareSubnets(subnets[i], *after) > 0
+		})
+	}
+
+	for start < len(subnets) {
+		end := start + pageSize
+		if end > len(subnets) {
+			end = len(subnets)
+		}
+		page := &SubnetPage{}
+		s.lock.RLock()
+		for _, subnet := range subnets[start:end] {
+			page.Subnets = append(page.Subnets, s.subnetMessage(listType, subnet, now))
+		}
+		s.lock.RUnlock()
+		if end < len(subnets) {
+			page.NextPageToken = subnets[end-1].String()
+		}
+		if err := stream.SendMsg(page); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// sortedList returns the unexpired subnets of the list in listing order.
+func (s *Service) sortedList(listType string, now time.Time) []net.IPNet {
+	s.lock.RLock()
+	subnets := make([]net.IPNet, 0, len(s.config.Lists[listType]))
+	for _, subnet := range s.config.Lists[listType] {
+		if !s.entries[listType][subnet.String()].expired(now) {
+			subnets = append(subnets, subnet)
+		}
+	}
+	s.lock.RUnlock()
+
+	sort.Slice(subnets, func(i, j int) bool {
+		return compareSubnets(subnets[i], subnets[j]) < 0
+	})
+	return subnets
+}
+
+func compareSubnets(a net.IPNet, b net.IPNet) int {
+	if result := bytes.Compare(a.IP.To16(), b.IP.To16()); result != 0 {
+		return result
+	}
+	aOnes, _ := a.Mask.Size()
+	bOnes, _ := b.Mask.Size()
+	return aOnes - bOnes
+}
@@ -0,0 +1,141 @@
+package bouncer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestListInspection(t *testing.T) {
+	service := newTestService(t, nil)
+	startTestService(t, service, false)
+	client := NewBouncerAdminClient(dialTestService(t, service.listener))
+	ctx := context.Background()
+
+	for i := 9; i >= 0; i-- {
+		require.Nil(t, service.AddSubnetToList(fmt.Sprintf("10.0.%d.0/24", i), "black"))
+	}
+	require.Nil(t, service.AddSubnetToList("10.0.0.0/8", "black"))
+	require.Nil(t, service.AddSubnetToList("10.0.5.7/32", "white"))
+
+	readPages := func(t *testing.T, request *ListRequest) []*SubnetPage {
+		stream, err := client.ListBlackList(ctx, request)
+		require.Nil(t, err)
+		pages := []*SubnetPage{}
+		for {
+			page, err := stream.Recv()
+			if err == io.EOF {
+				return pages
+			}
+			require.Nil(t, err)
+			pages = append(pages, page)
+		}
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		pages := readPages(t, &ListRequest{PageSize: 4})
+		require.Len(t, pages, 3)
+		require.Equal(t, "10.0.0.0/8", pages[0].Subnets[0].Subnet)
+		require.Equal(t, "10.0.0.0/24", pages[0].Subnets[1].Subnet)
+		require.Equal(t, "10.0.2.0/24", pages[0].NextPageToken)
+		require.Len(t, pages[2].Subnets, 3)
+		require.Empty(t, pages[2].NextPageToken)
+
+		resumed := readPages(t, &ListRequest{PageSize: 4, PageToken: pages[0].NextPageToken})
+		require.Equal(t, pages[1:], resumed)
+
+		require.Len(t, readPages(t, &ListRequest{}), 1)
+	})
+
+	t.Run("white list", func(t *testing.T) {
+		stream, err := client.ListWhiteList(ctx, &ListRequest{})
+		require.Nil(t, err)
+		page, err := stream.Recv()
+		require.Nil(t, err)
+		require.Equal(t, "10.0.5.7/32", page.Subnets[0].Subnet)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		for _, request := range []*ListRequest{{PageSize: -1}, {PageSize: maxPageSize + 1}, {PageToken: "token"}} {
+			stream, err := client.ListBlackList(ctx, request)
+			require.Nil(t, err)
+			_, err = stream.Recv()
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("temporary entries", func(t *testing.T) {
+		_, err := client.AddBlackList(ctx, &Subnet{Subnet: "192.0.2.0/24", Ttl: durationpb.New(time.Hour), Comment: "brute force"})
+		require.Nil(t, err)
+		_, err = client.AddBlackList(ctx, &Subnet{Subnet: "203.0.113.0/24", Ttl: durationpb.New(-time.Hour)})
+		require.Error(t, err)
+
+		response, err := client.CheckIP(ctx, &CheckIPRequest{Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		require.Equal(t, "brute force", response.Subnet.Comment)
+		ttl := response.Subnet.Ttl.AsDuration()
+		require.True(t, ttl > 0 && ttl <= time.Hour)
+
+		service.expireListEntries(time.Now().Add(time.Hour))
+		response, err = client.CheckIP(ctx, &CheckIPRequest{Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		require.Equal(t, Reason_OK, response.Reason)
+		require.Len(t, readPages(t, &ListRequest{PageSize: maxPageSize})[0].Subnets, 11)
+	})
+
+	t.Run("expired before the sweep", func(t *testing.T) {
+		_, err := client.AddBlackList(ctx, &Subnet{Subnet: "198.51.100.0/24"})
+		require.Nil(t, err)
+		_, err = client.AddWhiteList(ctx, &Subnet{Subnet: "198.51.100.0/25", Ttl: durationpb.New(20 * time.Millisecond)})
+		require.Nil(t, err)
+		time.Sleep(50 * time.Millisecond)
+
+		response, err := client.CheckIP(ctx, &CheckIPRequest{Ip: "198.51.100.1"})
+		require.Nil(t, err)
+		require.Equal(t, "198.51.100.0/24", response.Subnet.Subnet)
+		require.Nil(t, response.Subnet.Ttl)
+		authorization, err := service.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "198.51.100.1"})
+		require.Nil(t, err)
+		require.Equal(t, Reason_BLACKLISTED, authorization.Reason)
+
+		stream, err := client.ListWhiteList(ctx, &ListRequest{})
+		require.Nil(t, err)
+		page, err := stream.Recv()
+		require.Nil(t, err)
+		require.Len(t, page.Subnets, 1)
+
+		_, err = client.RemoveBlackList(ctx, &Subnet{Subnet: "198.51.100.0/24"})
+		require.Nil(t, err)
+		service.expireListEntries(time.Now())
+	})
+
+	t.Run("check IP", func(t *testing.T) {
+		response, err := client.CheckIP(ctx, &CheckIPRequest{Ip: "10.0.5.7"})
+		require.Nil(t, err)
+		require.Equal(t, "white", response.List)
+		require.Equal(t, "10.0.5.7/32", response.Subnet.Subnet)
+		require.Equal(t, Reason_WHITELISTED, response.Reason)
+
+		response, err = client.CheckIP(ctx, &CheckIPRequest{Ip: "10.0.5.8"})
+		require.Nil(t, err)
+		require.Equal(t, "black", response.List)
+		require.Equal(t, "10.0.5.0/24", response.Subnet.Subnet)
+		require.Equal(t, Reason_BLACKLISTED, response.Reason)
+
+		response, err = client.CheckIP(ctx, &CheckIPRequest{Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		require.Empty(t, response.List)
+		require.Nil(t, response.Subnet)
+		require.Equal(t, Reason_OK, response.Reason)
+
+		_, err = client.CheckIP(ctx, &CheckIPRequest{Ip: "10.0.5"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
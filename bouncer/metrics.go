@@ -0,0 +1,184 @@
+package bouncer
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const metricsNamespace = "bouncer"
+
+// metrics are registered in a registry of their own, so several services
+// can live in one process. Bucket counts and list sizes are read on scrape.
+type metrics struct {
+	registry       *prometheus.Registry
+	authorizations *prometheus.CounterVec
+	sweeps         prometheus.Counter
+	sweptBuckets   prometheus.Counter
+	rpcDuration    *prometheus.HistogramVec
+	storeErrors    *prometheus.CounterVec
+	auditErrors    prometheus.Counter
+}
+
+type serviceCollector struct {
+	service          *Service
+	buckets          *prometheus.Desc
+	listSize         *prometheus.Desc
+	shadowRejections *prometheus.Desc
+}
+
+func newMetrics(s *Service) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		authorizations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "authorizations_total",
+			Help:      "Authorization decisions by reason.",
+		}, []string{"reason"}),
+		sweeps: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_sweeps_total",
+			Help:      "Sweeps of empty buckets.",
+		}),
+		sweptBuckets: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_deleted_buckets_total",
+			Help:      "Buckets deleted by sweeps.",
+		}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Latency of gRPC calls by method and status code.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}, []string{"method", "code"}),
+		storeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "store_errors_total",
+			Help:      "Failed bucket store operations by operation.",
+		}, []string{"operation"}),
+		auditErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "audit_errors_total",
+			Help:      "Audit records that could not be written.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.authorizations,
+		m.sweeps,
+		m.sweptBuckets,
+		m.rpcDuration,
+		m.storeErrors,
+		m.auditErrors,
+		&serviceCollector{
+			service: s,
+			buckets: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "buckets"),
+				"Live buckets by type.", []string{"type"}, nil),
+			listSize: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "list_subnets"),
+				"Subnets in the black and white lists.", []string{"list"}, nil),
+			shadowRejections: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "shadow_rejections_total"),
+				"Rejections only logged in shadow mode, by reason.", []string{"reason"}, nil),
+		},
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+func (m *metrics) observeAuthorization(response *AuthResponse) {
+	if m == nil {
+		return
+	}
+	m.authorizations.WithLabelValues(response.Reason.String()).Inc()
+}
+
+func (m *metrics) observeSweep(deleted int) {
+	if m == nil {
+		return
+	}
+	m.sweeps.Inc()
+	m.sweptBuckets.Add(float64(deleted))
+}
+
+func (m *metrics) observeStoreError(operation string) {
+	if m == nil {
+		return
+	}
+	m.storeErrors.WithLabelValues(operation).Inc()
+}
+
+func (m *metrics) observeAuditError() {
+	if m == nil {
+		return
+	}
+	m.auditErrors.Inc()
+}
+
+func (m *metrics) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.rpcDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+func (m *metrics) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	m.rpcDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (c *serviceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.buckets
+	ch <- c.listSize
+	ch <- c.shadowRejections
+}
+
+func (c *serviceCollector) Collect(ch chan<- prometheus.Metric) {
+	_, limiters := c.service.settings()
+	counts := map[string]int{}
+	if counter, ok := c.service.store.(bucketCounter); ok {
+		counts = counter.Count()
+	}
+	for bucketType, limiter := range limiters {
+		if sized, ok := limiter.(sizedLimiter); ok {
+			counts[bucketType] = sized.Len()
+		}
+	}
+	for bucketType, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.buckets, prometheus.GaugeValue, float64(count), bucketType)
+	}
+
+	c.service.lock.RLock()
+	for listType, trie := range c.service.lists {
+		ch <- prometheus.MustNewConstMetric(c.listSize, prometheus.GaugeValue, float64(trie.Len()), listType)
+	}
+	c.service.lock.RUnlock()
+
+	for reason, count := range c.service.ShadowRejections() {
+		ch <- prometheus.MustNewConstMetric(c.shadowRejections, prometheus.CounterValue, float64(count), reason.String())
+	}
+}
+
+// initMetricsServer serves /metrics on MetricsAdress. The endpoint is
+// disabled when the address is empty.
+func (s *Service) initMetricsServer() {
+	if s.config.MetricsAdress == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+	s.metricsServer = &http.Server{Addr: s.config.MetricsAdress, Handler: mux}
+	go func() {
+		log.Printf("Serving metrics on %s", s.config.MetricsAdress)
+		if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Serving metrics: %v", err)
+		}
+	}()
+}
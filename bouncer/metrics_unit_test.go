@@ -0,0 +1,52 @@
+package bouncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	const testLogin = "login"
+
+	service := newTestService(t, func(config *ConfigStruct) {
+		config.Limit["login"] = 2
+		config.Algorithm = map[string]string{"ip": AlgorithmSlidingLog}
+	})
+	service.initValues()
+	require.Nil(t, service.AddSubnetToList("192.0.2.0/24", "black"))
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Authorization(context.Background(), &AuthRequest{Login: testLogin, Password: "password", Ip: "198.51.100.1"})
+		require.Nil(t, err)
+	}
+	_, err := service.Authorization(context.Background(), &AuthRequest{Login: testLogin, Password: "password", Ip: "192.0.2.1"})
+	require.Nil(t, err)
+
+	authorizations := service.metrics.authorizations
+	require.Equal(t, float64(2), testutil.ToFloat64(authorizations.WithLabelValues(Reason_OK.String())))
+	require.Equal(t, float64(1), testutil.ToFloat64(authorizations.WithLabelValues(Reason_LOGIN_LIMIT.String())))
+	require.Equal(t, float64(1), testutil.ToFloat64(authorizations.WithLabelValues(Reason_BLACKLISTED.String())))
+
+	families, err := service.metrics.registry.Gather()
+	require.Nil(t, err)
+	gauges := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				gauges[family.GetName()+"/"+label.GetValue()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	require.Equal(t, float64(1), gauges["bouncer_buckets/login"])
+	require.Equal(t, float64(1), gauges["bouncer_buckets/ip"])
+	require.Equal(t, float64(1), gauges["bouncer_list_subnets/black"])
+	require.Equal(t, float64(0), gauges["bouncer_list_subnets/white"])
+
+	service.RemoveEmptyBuckets()
+	service.RemoveEmptyBuckets()
+	require.Equal(t, float64(2), testutil.ToFloat64(service.metrics.sweeps))
+	require.Equal(t, float64(2), testutil.ToFloat64(service.metrics.sweptBuckets))
+}
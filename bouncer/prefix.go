@@ -0,0 +1,43 @@
+package bouncer
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// IPPrefixConfig groups addresses into networks of the given prefix length
+// that share a "subnet" bucket on top of the per address "ip" bucket, so an
+// attacker can not escape the ip limit by rotating addresses within its
+// allocation. A zero length turns the grouping off for the address family.
+type IPPrefixConfig struct {
+	V4 int
+	V6 int
+}
+
+// canonicalIP parses the address so that every spelling of an IP,
+// like "::1" and "0:0::1", maps to the same buckets.
+func canonicalIP(address string) (net.IP, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, errors.Errorf("Invalid IP address %q", address)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4, nil
+	}
+	return ip, nil
+}
+
+// prefixOf returns the network the ip is grouped into, or nil when the
+// grouping is off for its family.
+func (config IPPrefixConfig) prefixOf(ip net.IP) *net.IPNet {
+	ones, bits := config.V6, 128
+	if len(ip) == net.IPv4len {
+		ones, bits = config.V4, 32
+	}
+	if ones <= 0 {
+		return nil
+	}
+	mask := net.CIDRMask(ones, bits)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
@@ -0,0 +1,88 @@
+package bouncer
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIPPrefixBuckets(t *testing.T) {
+	newService := func() *Service {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.Limit = map[string]int{"login": 100, "password": 100, "ip": 2, "subnet": 3}
+			config.IPPrefix = IPPrefixConfig{V6: 64}
+		})
+		service.initValues()
+		return service
+	}
+	ctx := context.Background()
+	authorize := func(t *testing.T, service *Service, ip string) *AuthResponse {
+		response, err := service.Authorization(ctx, &AuthRequest{Login: ip, Password: "password", Ip: ip})
+		require.Nil(t, err)
+		return response
+	}
+
+	t.Run("canonical addresses share a bucket", func(t *testing.T) {
+		service := newService()
+		require.True(t, authorize(t, service, "2001:db8::1").Ok)
+		require.True(t, authorize(t, service, "2001:0db8:0:0::1").Ok)
+		require.Equal(t, Reason_IP_LIMIT, authorize(t, service, "2001:DB8::0:1").Reason)
+
+		require.True(t, authorize(t, service, "192.0.2.1").Ok)
+		require.True(t, authorize(t, service, "::ffff:192.0.2.1").Ok)
+		require.Equal(t, Reason_IP_LIMIT, authorize(t, service, "192.0.2.1").Reason)
+	})
+
+	t.Run("network bucket", func(t *testing.T) {
+		service := newService()
+		require.True(t, authorize(t, service, "2001:db8::1").Ok)
+		require.True(t, authorize(t, service, "2001:db8::2").Ok)
+		require.True(t, authorize(t, service, "2001:db8::3").Ok)
+		require.Equal(t, Reason_SUBNET_LIMIT, authorize(t, service, "2001:db8::4").Reason)
+		require.True(t, authorize(t, service, "2001:db8:0:1::1").Ok)
+
+		for i := 1; i <= 4; i++ {
+			require.True(t, authorize(t, service, net.IPv4(198, 51, 100, byte(i)).String()).Ok)
+		}
+
+		_, err := service.DropBucket(ctx, &DropBucketParams{Login: "2001:db8::4", Ip: "2001:db8::4"})
+		require.Nil(t, err)
+		require.True(t, authorize(t, service, "2001:db8::4").Ok)
+	})
+
+	t.Run("invalid addresses", func(t *testing.T) {
+		service := newService()
+		for _, ip := range []string{"", "localhost", "192.0.2.256", "2001:db8::/64"} {
+			_, err := service.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: ip})
+			require.Equal(t, codes.InvalidArgument, status.Code(err), ip)
+		}
+		_, err := service.DropBucket(ctx, &DropBucketParams{Login: "login", Ip: "localhost"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.GetBucketState(ctx, &BucketStateRequest{Ip: "localhost"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("bucket state", func(t *testing.T) {
+		service := newService()
+		authorize(t, service, "2001:db8::1")
+		response, err := service.GetBucketState(ctx, &BucketStateRequest{Ip: "2001:db8::2"})
+		require.Nil(t, err)
+		require.Len(t, response.Buckets, 2)
+		require.Equal(t, "subnet", response.Buckets[1].Type)
+		require.Equal(t, int64(1), response.Buckets[1].Used)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		config := newService().currentConfig()
+		require.Nil(t, config.validate())
+		config.IPPrefix.V4 = 33
+		require.Error(t, config.validate())
+		config.IPPrefix.V4 = 24
+		config.Limit = map[string]int{"login": 100, "password": 100, "ip": 2}
+		require.Error(t, config.validate())
+	})
+}
@@ -0,0 +1,241 @@
+package bouncer
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+// configDebounce merges the burst of events produced by editors
+// and by Kubernetes ConfigMap symlink swaps into one reload.
+const configDebounce = 200 * time.Millisecond
+
+var requiredBucketTypes = []string{"login", "password", "ip"}
+
+func (config ConfigStruct) validate() error {
+	if config.TimerSec <= 0 {
+		return errors.Errorf("TimerSec must be positive, got %d", config.TimerSec)
+	}
+	for _, bucketType := range requiredBucketTypes {
+		if config.Limit[bucketType] <= 0 {
+			return errors.Errorf("Limit for %q must be positive, got %d", bucketType, config.Limit[bucketType])
+		}
+	}
+	if config.IPPrefix.V4 < 0 || config.IPPrefix.V4 > 32 {
+		return errors.Errorf("IPv4 prefix length must be between 0 and 32, got %d", config.IPPrefix.V4)
+	}
+	if config.IPPrefix.V6 < 0 || config.IPPrefix.V6 > 128 {
+		return errors.Errorf("IPv6 prefix length must be between 0 and 128, got %d", config.IPPrefix.V6)
+	}
+	if (config.IPPrefix.V4 > 0 || config.IPPrefix.V6 > 0) && config.Limit["subnet"] <= 0 {
+		return errors.Errorf("Limit for \"subnet\" must be positive when IPPrefix is set, got %d", config.Limit["subnet"])
+	}
+	for bucketType, algorithm := range config.Algorithm {
+		if !knownAlgorithm(algorithm) {
+			return errors.Errorf("Unknown algorithm %q for bucket type %q", algorithm, bucketType)
+		}
+	}
+	switch config.Storage.Type {
+	case "", StorageMemory, StorageRedis:
+	default:
+		return errors.Errorf("Unknown storage type %q", config.Storage.Type)
+	}
+	// Only leaky buckets are kept in the store, the other algorithms would
+	// enforce the limit per replica.
+	if config.Storage.Type == StorageRedis {
+		for bucketType := range config.Limit {
+			if algorithm := algorithmOf(config, bucketType); algorithm != AlgorithmLeaky {
+				return errors.Errorf("Algorithm %q for bucket type %q can not be shared through redis storage", algorithm, bucketType)
+			}
+		}
+	}
+	if escalation := config.Escalation; escalation.Trips < 0 {
+		return errors.Errorf("Escalation trips must not be negative, got %d", escalation.Trips)
+	} else if escalation.Trips > 0 && (escalation.WindowSec <= 0 || escalation.BanSec <= 0) {
+		return errors.Errorf("Escalation needs a positive WindowSec and BanSec")
+	} else if escalation.MaxBanSec != 0 && escalation.MaxBanSec < escalation.BanSec {
+		return errors.Errorf("Escalation MaxBanSec %d is shorter than BanSec %d", escalation.MaxBanSec, escalation.BanSec)
+	}
+	for _, client := range config.Access.Clients {
+		if client.Name == "" || (client.Token == "" && client.Subject == "") {
+			return errors.Errorf("Client %q needs a name and a token or a subject", client.Name)
+		}
+		for _, role := range client.Roles {
+			if role != RoleCheck && role != RoleAdmin {
+				return errors.Errorf("Unknown role %q for client %q", role, client.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) currentConfig() ConfigStruct {
+	config, _ := s.settings()
+	return config
+}
+
+func copyLists(lists map[string][]net.IPNet) map[string][]net.IPNet {
+	copied := map[string][]net.IPNet{}
+	for listType, subnets := range lists {
+		copied[listType] = append([]net.IPNet{}, subnets...)
+	}
+	return copied
+}
+
+// initReloader reloads the config on SIGHUP and on changes in the config directory.
+func (s *Service) initReloader(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	var events chan fsnotify.Event
+	var watchErrors chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = watcher.Add(filepath.Dir(s.configPath))
+		events, watchErrors = watcher.Events, watcher.Errors
+	}
+	if err != nil {
+		log.Printf("Watching config file: %v", err)
+	}
+
+	go func() {
+		var pending <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				signal.Stop(signals)
+				if watcher != nil {
+					watcher.Close()
+				}
+				return
+			case <-signals:
+				s.logReload(s.ReloadConfig())
+			case <-events:
+				pending = time.After(configDebounce)
+			case err := <-watchErrors:
+				log.Printf("Watching config file: %v", err)
+			case <-pending:
+				pending = nil
+				s.logReload(s.ReloadConfig())
+			}
+		}
+	}()
+}
+
+func (s *Service) logReload(err error) {
+	if err != nil {
+		log.Printf("Config is not reloaded: %v", err)
+	}
+}
+
+// ReloadConfig re-reads the config file and applies limits, algorithms,
+// shadow settings and static lists without dropping buckets. An invalid
+// config is rejected as a whole and the current one stays in effect.
+func (s *Service) ReloadConfig() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	config, err := readConfig(s.configPath)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(config, s.fileConfig) {
+		return nil
+	}
+
+	previous := s.fileConfig
+	for field, changed := range map[string]bool{
+		"ListenerAdress":      config.ListenerAdress != previous.ListenerAdress,
+		"AdminListenerAdress": config.AdminListenerAdress != previous.AdminListenerAdress,
+		"DisableLegacyAdmin":  config.DisableLegacyAdmin != previous.DisableLegacyAdmin,
+		"MetricsAdress":       config.MetricsAdress != previous.MetricsAdress,
+		"ShardCount":          config.ShardCount != previous.ShardCount,
+		"Storage":             config.Storage != previous.Storage,
+		"ListsJournal":        config.ListsJournal != previous.ListsJournal,
+		"Audit":               config.Audit != previous.Audit,
+		"HashSecret":          config.HashSecret != previous.HashSecret,
+		"TLS":                 config.TLS != previous.TLS,
+	} {
+		if changed {
+			log.Printf("Config field %s can not be reloaded, restart the service to apply it", field)
+		}
+	}
+
+	s.lock.Lock()
+	s.config.TimerSec = config.TimerSec
+	s.config.Limit = config.Limit
+	s.config.Algorithm = config.Algorithm
+	s.config.ShadowMode = config.ShadowMode
+	s.config.Shadow = config.Shadow
+	s.config.ShutdownTimeoutSec = config.ShutdownTimeoutSec
+	s.config.Access = config.Access
+	s.config.WatchBufferSize = config.WatchBufferSize
+	s.config.Escalation = config.Escalation
+	s.config.IPPrefix = config.IPPrefix
+
+	window := time.Duration(config.TimerSec) * time.Second
+	limiters := map[string]Limiter{}
+	for bucketType := range config.Limit {
+		limiter, ok := s.limiters[bucketType]
+		if ok && algorithmOf(previous, bucketType) == algorithmOf(config, bucketType) {
+			if tunable, ok := limiter.(tunableLimiter); ok {
+				tunable.tune(config.Limit[bucketType], window)
+			}
+		} else {
+			limiter, err = s.newLimiter(bucketType)
+			if err != nil {
+				s.lock.Unlock()
+				return err
+			}
+		}
+		limiters[bucketType] = limiter
+	}
+	s.limiters = limiters
+	remover := s.remover
+	s.lock.Unlock()
+
+	if remover != nil && config.TimerSec != previous.TimerSec {
+		remover.Reset(window)
+	}
+	if err := s.mergeStaticLists(previous.Lists, config.Lists); err != nil {
+		return err
+	}
+
+	s.fileConfig = config
+	s.fileConfig.Lists = copyLists(config.Lists)
+	log.Printf("Config reloaded from %s", s.configPath)
+	return nil
+}
+
+// mergeStaticLists applies only the difference between the old and the new
+// static lists, so subnets added or removed through the API stay as they are.
+func (s *Service) mergeStaticLists(previous map[string][]net.IPNet, current map[string][]net.IPNet) error {
+	for listType := range mergeListTypes(previous, current) {
+		previousSet := subnetSet(previous[listType])
+		currentSet := subnetSet(current[listType])
+		for subnet := range previousSet {
+			if !currentSet[subnet] {
+				if err := s.removeSubnet(subnet, listType); err != nil {
+					return err
+				}
+			}
+		}
+		for subnet := range currentSet {
+			if !previousSet[subnet] {
+				if err := s.addSubnet(subnet, listType, listEntry{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
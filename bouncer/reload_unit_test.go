@@ -0,0 +1,125 @@
+package bouncer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadConfig(t *testing.T) {
+	const testLogin = "login"
+
+	writeConfig := func(t *testing.T, path string, config ConfigStruct) {
+		content, err := json.Marshal(config)
+		require.Nil(t, err)
+		require.Nil(t, ioutil.WriteFile(path, content, 0o644))
+	}
+	newService := func(t *testing.T, config ConfigStruct) *Service {
+		service := &Service{configPath: filepath.Join(t.TempDir(), "config.json")}
+		writeConfig(t, service.configPath, config)
+		service.loadConfig()
+		service.initValues()
+		return service
+	}
+
+	t.Run("reload on file change keeps buckets", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		config := testConfig()
+		service := newService(t, config)
+		service.InitRemover(ctx)
+		service.initReloader(ctx)
+		for i := 0; i < config.Limit["login"]; i++ {
+			require.True(t, isAllowed(service.addToBucket("login", testLogin)))
+		}
+
+		config.Limit["login"] = 20
+		_, blackSubnet, _ := net.ParseCIDR("192.0.2.0/24")
+		config.Lists["black"] = []net.IPNet{*blackSubnet}
+		writeConfig(t, service.configPath, config)
+
+		require.Eventually(t, func() bool {
+			return service.currentConfig().Limit["login"] == 20
+		}, 5*time.Second, 10*time.Millisecond)
+		require.False(t, isAllowed(service.addToBucket("login", testLogin)))
+		isAlive, needCheck := service.checkLists("192.0.2.1")
+		require.False(t, isAlive)
+		require.False(t, needCheck)
+	})
+
+	t.Run("reload on SIGHUP", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		config := testConfig()
+		service := newService(t, config)
+		config.ShadowMode = true
+		writeConfig(t, service.configPath, config)
+		service.initReloader(ctx)
+
+		require.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		require.Eventually(t, func() bool {
+			return service.currentConfig().ShadowMode
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		config := testConfig()
+		service := newService(t, config)
+		config.TimerSec = 0
+		config.Limit["login"] = 1
+		writeConfig(t, service.configPath, config)
+
+		require.Error(t, service.ReloadConfig())
+		require.Equal(t, 10, service.currentConfig().Limit["login"])
+
+		config = testConfig()
+		config.Algorithm = map[string]string{"login": "unknown"}
+		writeConfig(t, service.configPath, config)
+		require.Error(t, service.ReloadConfig())
+	})
+
+	t.Run("algorithm switch and retune", func(t *testing.T) {
+		config := testConfig()
+		config.Algorithm = map[string]string{"password": AlgorithmToken}
+		service := newService(t, config)
+		tokenLimiter := service.limiters["password"]
+
+		config.Algorithm["login"] = AlgorithmSlidingLog
+		config.Limit["password"] = 5
+		writeConfig(t, service.configPath, config)
+		require.Nil(t, service.ReloadConfig())
+
+		_, limiters := service.settings()
+		require.IsType(t, &slidingLogLimiter{}, limiters["login"].(*shardedLimiter).shards[0])
+		require.Same(t, tokenLimiter, limiters["password"])
+		require.Equal(t, 5, limiters["password"].Stats(testLogin).Limit)
+	})
+
+	t.Run("static lists merge", func(t *testing.T) {
+		config := testConfig()
+		_, staticSubnet, _ := net.ParseCIDR("198.51.100.0/24")
+		config.Lists["white"] = []net.IPNet{*staticSubnet}
+		service := newService(t, config)
+		require.Nil(t, service.AddSubnetToList("203.0.113.0/24", "black"))
+
+		config.Lists["white"] = []net.IPNet{}
+		writeConfig(t, service.configPath, config)
+		require.Nil(t, service.ReloadConfig())
+
+		_, needCheck := service.checkLists("198.51.100.1")
+		require.True(t, needCheck)
+		isAlive, needCheck := service.checkLists("203.0.113.1")
+		require.False(t, isAlive)
+		require.False(t, needCheck)
+	})
+}
@@ -0,0 +1,65 @@
+package bouncer
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// testConfig returns the limits and lists most tests start from.
+func testConfig() ConfigStruct {
+	return ConfigStruct{
+		TimerSec: 60,
+		Limit:    map[string]int{"login": 10, "password": 100, "ip": 1000},
+		Lists:    map[string][]net.IPNet{"black": {}, "white": {}},
+	}
+}
+
+// newTestService returns a service with the test config changed by configure
+// and a lists journal in a temporary directory.
+func newTestService(t *testing.T, configure func(config *ConfigStruct)) *Service {
+	config := testConfig()
+	config.ListsJournal = filepath.Join(t.TempDir(), "lists.journal")
+	if configure != nil {
+		configure(&config)
+	}
+	return &Service{config: config}
+}
+
+// serveTestService serves the service on loopback listeners without making
+// it ready and shuts it down when the test ends. BouncerAdmin gets a listener
+// of its own when separateAdmin is set.
+func serveTestService(t *testing.T, service *Service, separateAdmin bool) {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	var adminLsn net.Listener
+	if separateAdmin {
+		adminLsn, err = net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+	}
+	require.Nil(t, service.initServer(lsn, adminLsn))
+	go service.server.Serve(lsn)
+	if adminLsn != nil {
+		go service.adminServer.Serve(adminLsn)
+	}
+	t.Cleanup(func() { service.ShutDown() })
+}
+
+// startTestService serves the service ready for calls.
+func startTestService(t *testing.T, service *Service, separateAdmin bool) {
+	serveTestService(t, service, separateAdmin)
+	service.initValues()
+	require.Nil(t, service.restoreLists())
+	service.setReady()
+}
+
+// dialTestService connects to the listener without TLS.
+func dialTestService(t *testing.T, lsn net.Listener) *grpc.ClientConn {
+	conn, err := grpc.Dial(lsn.Addr().String(), grpc.WithInsecure())
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
@@ -0,0 +1,33 @@
+package bouncer
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+// shadowCounters counts rejections that were not enforced because
+// of the shadow mode, indexed by the would-be reason.
+type shadowCounters struct {
+	counts [Reason_SUBNET_LIMIT + 1]uint64
+}
+
+func (config ConfigStruct) isShadowed(bucketType string) bool {
+	return config.ShadowMode || config.Shadow[bucketType]
+}
+
+// shadowReject logs the bucket key of the login, never the raw value.
+func (s *Service) shadowReject(loginKey string, ip string, reason Reason) {
+	atomic.AddUint64(&s.shadowRejections.counts[reason], 1)
+	log.Printf("Shadow mode: would reject login %s from %s with %s", loginKey, ip, reason)
+}
+
+// ShadowRejections returns the number of not enforced rejections by reason.
+func (s *Service) ShadowRejections() map[Reason]uint64 {
+	rejections := map[Reason]uint64{}
+	for reason := range s.shadowRejections.counts {
+		if count := atomic.LoadUint64(&s.shadowRejections.counts[reason]); count > 0 {
+			rejections[Reason(reason)] = count
+		}
+	}
+	return rejections
+}
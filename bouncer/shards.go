@@ -0,0 +1,200 @@
+package bouncer
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultShardCount = 32
+
+type bucketShard struct {
+	lock        sync.Mutex
+	bucketBunch map[string]buckets
+}
+
+// memoryStore is the default BucketStore. Buckets are spread over
+// hash-sharded maps so that each shard is locked independently.
+type memoryStore struct {
+	shards []*bucketShard
+}
+
+func newMemoryStore(shardCount int) *memoryStore {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+
+	store := &memoryStore{shards: make([]*bucketShard, shardCount)}
+	for i := range store.shards {
+		store.shards[i] = &bucketShard{bucketBunch: map[string]buckets{}}
+	}
+	return store
+}
+
+// shardIndex picks one of count shards by FNV-1a hash of the key.
+func shardIndex(key string, count int) int {
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return int(hash % uint32(count))
+}
+
+func (m *memoryStore) shardFor(bucketKey string) *bucketShard {
+	return m.shards[shardIndex(bucketKey, len(m.shards))]
+}
+
+func (m *memoryStore) Take(bucketType string, bucketKey string, limit int, rate float64, now time.Time) (bool, bucketDetail, error) {
+	shard := m.shardFor(bucketKey)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	bucketsByType, ok := shard.bucketBunch[bucketType]
+	if !ok {
+		bucketsByType = buckets{}
+		shard.bucketBunch[bucketType] = bucketsByType
+	}
+
+	curBucket, ok := bucketsByType[bucketKey]
+	if !ok {
+		curBucket = bucketDetail{
+			Tokens:     float64(limit),
+			LastRefill: now,
+		}
+	}
+	curBucket.Tokens = refillTokens(curBucket.Tokens, curBucket.LastRefill, now, rate, limit)
+	curBucket.LastRefill = now
+	curBucket.FlagToDelition = false
+
+	isAlive := curBucket.Tokens >= 1
+	if isAlive {
+		curBucket.Tokens--
+	}
+	bucketsByType[bucketKey] = curBucket
+
+	return isAlive, curBucket, nil
+}
+
+func (m *memoryStore) Get(bucketType string, bucketKey string) (bucketDetail, bool, error) {
+	shard := m.shardFor(bucketKey)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	curBucket, ok := shard.bucketBunch[bucketType][bucketKey]
+	return curBucket, ok, nil
+}
+
+func (m *memoryStore) set(bucketType string, bucketKey string, curBucket bucketDetail) {
+	shard := m.shardFor(bucketKey)
+	shard.lock.Lock()
+	shard.bucketBunch[bucketType][bucketKey] = curBucket
+	shard.lock.Unlock()
+}
+
+func (m *memoryStore) Remove(bucketType string, bucketKey string) error {
+	shard := m.shardFor(bucketKey)
+	shard.lock.Lock()
+	delete(shard.bucketBunch[bucketType], bucketKey)
+	shard.lock.Unlock()
+	return nil
+}
+
+// Sweep deletes buckets that were not touched since the previous sweep,
+// locking one shard at a time.
+func (m *memoryStore) Sweep() (int, error) {
+	deleted := 0
+	for _, shard := range m.shards {
+		shard.lock.Lock()
+		for bucketType, bucketsByType := range shard.bucketBunch {
+			for key, bucket := range bucketsByType {
+				if bucket.FlagToDelition {
+					delete(shard.bucketBunch[bucketType], key)
+					deleted++
+				} else {
+					bucket.FlagToDelition = true
+					shard.bucketBunch[bucketType][key] = bucket
+				}
+			}
+		}
+		shard.lock.Unlock()
+	}
+	return deleted, nil
+}
+
+func (m *memoryStore) Count() map[string]int {
+	counts := map[string]int{}
+	for _, shard := range m.shards {
+		shard.lock.Lock()
+		for bucketType, bucketsByType := range shard.bucketBunch {
+			counts[bucketType] += len(bucketsByType)
+		}
+		shard.lock.Unlock()
+	}
+	return counts
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
+
+// memoryLimiter is a limiter keeping its keys in a map of its own.
+type memoryLimiter interface {
+	sizedLimiter
+	tune(limit int, window time.Duration)
+}
+
+// shardedLimiter spreads the keys of an in-memory limiter over shards that
+// are locked independently, as memoryStore does for the leaky buckets.
+type shardedLimiter struct {
+	shards []memoryLimiter
+}
+
+func newShardedLimiter(shardCount int, newShard func() memoryLimiter) *shardedLimiter {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+
+	limiter := &shardedLimiter{shards: make([]memoryLimiter, shardCount)}
+	for i := range limiter.shards {
+		limiter.shards[i] = newShard()
+	}
+	return limiter
+}
+
+func (l *shardedLimiter) shardFor(key string) memoryLimiter {
+	return l.shards[shardIndex(key, len(l.shards))]
+}
+
+func (l *shardedLimiter) Allow(key string) (bool, LimiterStats) {
+	return l.shardFor(key).Allow(key)
+}
+
+func (l *shardedLimiter) Reset(key string) {
+	l.shardFor(key).Reset(key)
+}
+
+func (l *shardedLimiter) Stats(key string) LimiterStats {
+	return l.shardFor(key).Stats(key)
+}
+
+func (l *shardedLimiter) Sweep() int {
+	deleted := 0
+	for _, shard := range l.shards {
+		deleted += shard.Sweep()
+	}
+	return deleted
+}
+
+func (l *shardedLimiter) Len() int {
+	count := 0
+	for _, shard := range l.shards {
+		count += shard.Len()
+	}
+	return count
+}
+
+func (l *shardedLimiter) tune(limit int, window time.Duration) {
+	for _, shard := range l.shards {
+		shard.tune(limit, window)
+	}
+}
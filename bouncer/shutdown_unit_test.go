@@ -0,0 +1,91 @@
+package bouncer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestShutDown(t *testing.T) {
+	newServer := func(t *testing.T, delay time.Duration, timeoutSec int64) (*Service, BouncerClient, chan struct{}) {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.ShutdownTimeoutSec = timeoutSec
+		})
+		service.initValues()
+		require.Nil(t, service.restoreLists())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		service.cancel = cancel
+		service.InitRemover(ctx)
+
+		started := make(chan struct{}, 1)
+		lsn, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		service.listener = lsn
+		service.server = grpc.NewServer(grpc.UnaryInterceptor(
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				started <- struct{}{}
+				time.Sleep(delay)
+				return handler(ctx, req)
+			}))
+		RegisterBouncerServer(service.server, service)
+		go service.server.Serve(lsn)
+		t.Cleanup(func() { service.ShutDown() })
+		return service, NewBouncerClient(dialTestService(t, lsn)), started
+	}
+
+	t.Run("in-flight request is drained", func(t *testing.T) {
+		service, client, started := newServer(t, 200*time.Millisecond, 5)
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := client.Authorization(context.Background(), &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+			result <- err
+		}()
+		<-started
+
+		require.Nil(t, service.ShutDown())
+		require.Nil(t, <-result)
+		require.Nil(t, service.ShutDown())
+		require.Error(t, service.AddSubnetToList("192.0.2.0/24", "black"))
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		service, client, started := newServer(t, 2*time.Second, 1)
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := client.Authorization(context.Background(), &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+			result <- err
+		}()
+		<-started
+
+		require.Equal(t, ErrShutdownTimeout, service.ShutDown())
+		require.Error(t, <-result)
+	})
+
+	t.Run("health watch ends", func(t *testing.T) {
+		service := newTestService(t, func(config *ConfigStruct) {
+			config.ShutdownTimeoutSec = 5
+		})
+		startTestService(t, service, false)
+		stream, err := healthpb.NewHealthClient(dialTestService(t, service.listener)).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+		require.Nil(t, err)
+		response, err := stream.Recv()
+		require.Nil(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+
+		require.Nil(t, service.ShutDown())
+		for err == nil {
+			_, err = stream.Recv()
+		}
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
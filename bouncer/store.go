@@ -0,0 +1,58 @@
+package bouncer
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StorageMemory = "memory"
+	StorageRedis  = "redis"
+)
+
+// BucketStore keeps the state of leaky buckets. Take refills the bucket
+// at rate tokens per second, takes one token if there is any left and
+// returns the bucket state after that. Sweep returns the number of
+// deleted buckets.
+type BucketStore interface {
+	Take(bucketType string, bucketKey string, limit int, rate float64, now time.Time) (bool, bucketDetail, error)
+	Get(bucketType string, bucketKey string) (bucketDetail, bool, error)
+	Remove(bucketType string, bucketKey string) error
+	Sweep() (int, error)
+	Close() error
+}
+
+// bucketCounter is implemented by stores that can count their buckets
+// cheaply, by bucket type.
+type bucketCounter interface {
+	Count() map[string]int
+}
+
+// StorageConfig selects the bucket store. Attempts are let through while
+// the store fails unless FailClosed is set, then they are rejected.
+type StorageConfig struct {
+	Type       string
+	Address    string
+	Password   string
+	DB         int
+	KeyPrefix  string
+	FailClosed bool
+}
+
+// storeError counts the failed store operation and logs it.
+func (s *Service) storeError(operation string, err error) {
+	s.metrics.observeStoreError(operation)
+	s.storeErrors.log(err, time.Now())
+}
+
+func (s *Service) newStore() (BucketStore, error) {
+	switch s.config.Storage.Type {
+	case "", StorageMemory:
+		return newMemoryStore(s.config.ShardCount), nil
+	case StorageRedis:
+		return newRedisStore(s.config.Storage)
+	}
+
+	return nil, errors.Errorf("Unknown storage type %q", s.config.Storage.Type)
+}
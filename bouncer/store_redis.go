@@ -0,0 +1,135 @@
+package bouncer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+const defaultKeyPrefix = "bouncer:"
+
+// takeScript refills and takes a token atomically, so replicas sharing
+// the same Redis never grant more attempts than the limit allows.
+// ARGV: limit, rate (tokens per second), now (unix microseconds), ttl (milliseconds).
+// The timestamp is stored as passed, Lua 5.1 would format the number in
+// exponent notation.
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local last = tonumber(state[2])
+if tokens == nil or last == nil then
+	tokens = limit
+	last = now
+end
+if now > last then
+	tokens = math.min(limit, tokens + (now - last) / 1000000 * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", ARGV[3])
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return {allowed, tostring(tokens)}
+`)
+
+// redisStore keeps buckets in Redis hashes. Keys expire once the bucket
+// would be full again, so no sweeping is needed.
+type redisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func newRedisStore(config StorageConfig) (*redisStore, error) {
+	keyPrefix := config.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Address,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "Connecting to redis")
+	}
+
+	return &redisStore{client: client, keyPrefix: keyPrefix}, nil
+}
+
+func (r *redisStore) key(bucketType string, bucketKey string) string {
+	return r.keyPrefix + bucketType + ":" + bucketKey
+}
+
+// refillTime is how long an empty bucket takes to be full again, the key
+// has to live that long. It follows the limit and the rate of every call, so
+// a reloaded TimerSec applies to the keys at once.
+func refillTime(limit int, rate float64) time.Duration {
+	return time.Duration(float64(limit) / rate * float64(time.Second)).Round(time.Millisecond)
+}
+
+func (r *redisStore) Take(bucketType string, bucketKey string, limit int, rate float64, now time.Time) (bool, bucketDetail, error) {
+	result, err := takeScript.Run(context.Background(), r.client, []string{r.key(bucketType, bucketKey)},
+		limit, rate, now.UnixNano()/int64(time.Microsecond), refillTime(limit, rate).Milliseconds()).Result()
+	if err != nil {
+		return false, bucketDetail{}, errors.Wrap(err, "Taking from redis bucket")
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, bucketDetail{}, errors.Errorf("Unexpected redis script result %v", result)
+	}
+	allowed, _ := values[0].(int64)
+	tokensValue, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return false, bucketDetail{}, errors.Wrap(err, "Parsing redis bucket")
+	}
+
+	return allowed == 1, bucketDetail{Tokens: tokens, LastRefill: now}, nil
+}
+
+func (r *redisStore) Get(bucketType string, bucketKey string) (bucketDetail, bool, error) {
+	state, err := r.client.HMGet(context.Background(), r.key(bucketType, bucketKey), "tokens", "ts").Result()
+	if err != nil {
+		return bucketDetail{}, false, errors.Wrap(err, "Getting redis bucket")
+	}
+
+	tokensValue, ok := state[0].(string)
+	if !ok {
+		return bucketDetail{}, false, nil
+	}
+	lastValue, _ := state[1].(string)
+
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return bucketDetail{}, false, errors.Wrap(err, "Parsing redis bucket")
+	}
+	last, err := strconv.ParseInt(lastValue, 10, 64)
+	if err != nil {
+		return bucketDetail{}, false, errors.Wrap(err, "Parsing redis bucket")
+	}
+
+	return bucketDetail{Tokens: tokens, LastRefill: time.Unix(0, last*int64(time.Microsecond))}, true, nil
+}
+
+func (r *redisStore) Remove(bucketType string, bucketKey string) error {
+	return errors.Wrap(r.client.Del(context.Background(), r.key(bucketType, bucketKey)).Err(), "Removing redis bucket")
+}
+
+func (r *redisStore) Sweep() (int, error) {
+	return 0, nil
+}
+
+func (r *redisStore) Close() error {
+	return r.client.Close()
+}
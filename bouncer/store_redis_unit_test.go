@@ -0,0 +1,135 @@
+package bouncer
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	const limit = 10
+	const testLogin = "login"
+
+	redisServer, err := miniredis.Run()
+	require.Nil(t, err)
+	defer redisServer.Close()
+
+	newReplica := func() *Service {
+		replica := newTestService(t, func(config *ConfigStruct) {
+			config.Limit = map[string]int{"login": limit}
+			config.Storage = StorageConfig{Type: StorageRedis, Address: redisServer.Addr()}
+			config.HashSecret = "secret"
+		})
+		replica.initValues()
+		return replica
+	}
+
+	t.Run("limit shared between replicas", func(t *testing.T) {
+		redisServer.FlushAll()
+		first, second := newReplica(), newReplica()
+		for i := 0; i < limit/2; i++ {
+			require.True(t, isAllowed(first.addToBucket("login", testLogin)))
+			require.True(t, isAllowed(second.addToBucket("login", testLogin)))
+		}
+		require.False(t, isAllowed(first.addToBucket("login", testLogin)))
+		require.False(t, isAllowed(second.addToBucket("login", testLogin)))
+
+		curBucket, ok := first.getBucket("login", testLogin)
+		require.True(t, ok)
+		require.Less(t, curBucket.Tokens, float64(1))
+	})
+
+	t.Run("timestamp stored as integer", func(t *testing.T) {
+		redisServer.FlushAll()
+		replica := newReplica()
+		replica.addToBucket("login", testLogin)
+
+		store := replica.store.(*redisStore)
+		ts := redisServer.HGet(store.key("login", testLogin), "ts")
+		_, err := strconv.ParseInt(ts, 10, 64)
+		require.Nil(t, err, ts)
+	})
+
+	t.Run("bucket removing", func(t *testing.T) {
+		redisServer.FlushAll()
+		replica := newReplica()
+		for i := 0; i <= limit; i++ {
+			replica.addToBucket("login", testLogin)
+		}
+		replica.RemoveBucket("login", testLogin)
+		require.True(t, isAllowed(replica.addToBucket("login", testLogin)))
+
+		curBucket, ok := replica.getBucket("login", testLogin)
+		require.True(t, ok)
+		require.InDelta(t, float64(limit-1), curBucket.Tokens, 0.01)
+	})
+
+	t.Run("bucket expiration", func(t *testing.T) {
+		redisServer.FlushAll()
+		replica := newReplica()
+		replica.addToBucket("login", testLogin)
+		redisServer.FastForward(time.Duration(replica.config.TimerSec) * time.Second)
+
+		_, ok := replica.getBucket("login", testLogin)
+		require.False(t, ok)
+	})
+
+	t.Run("expiration follows reloaded timer", func(t *testing.T) {
+		redisServer.FlushAll()
+		replica := newReplica()
+		key := replica.store.(*redisStore).key("login", testLogin)
+		replica.addToBucket("login", testLogin)
+		require.InDelta(t, time.Minute, redisServer.TTL(key), float64(time.Second))
+
+		replica.lock.Lock()
+		replica.config.TimerSec = 600
+		replica.lock.Unlock()
+		replica.addToBucket("login", testLogin)
+		require.InDelta(t, 10*time.Minute, redisServer.TTL(key), float64(time.Second))
+	})
+
+	t.Run("only leaky buckets", func(t *testing.T) {
+		config := testConfig()
+		config.Storage = StorageConfig{Type: StorageRedis, Address: redisServer.Addr()}
+		require.Nil(t, config.validate())
+		config.Algorithm = map[string]string{"ip": AlgorithmToken}
+		require.Error(t, config.validate())
+	})
+
+	t.Run("redis outage", func(t *testing.T) {
+		var output bytes.Buffer
+		log.SetOutput(&output)
+		defer log.SetOutput(os.Stderr)
+
+		for _, failClosed := range []bool{false, true} {
+			outageServer, err := miniredis.Run()
+			require.Nil(t, err)
+			replica := newTestService(t, func(config *ConfigStruct) {
+				config.Limit = map[string]int{"login": limit}
+				config.Storage = StorageConfig{Type: StorageRedis, Address: outageServer.Addr(), FailClosed: failClosed}
+				config.HashSecret = "secret"
+			})
+			replica.initValues()
+			outageServer.Close()
+
+			for i := 0; i < 3; i++ {
+				require.Equal(t, !failClosed, isAllowed(replica.addToBucket("login", testLogin)))
+			}
+			require.Equal(t, float64(3), testutil.ToFloat64(replica.metrics.storeErrors.WithLabelValues("take")))
+		}
+		require.Equal(t, 2, strings.Count(output.String(), "Adding to bucket"))
+	})
+
+	t.Run("unreachable redis", func(t *testing.T) {
+		_, err := newRedisStore(StorageConfig{Address: "127.0.0.1:1"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,137 @@
+package bouncer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig enables TLS on the gRPC listener when CertFile is set, and
+// requires client certificates signed by ClientCAFile when it is set.
+// MinVersion is "1.2" or "1.3", "1.2" by default.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	MinVersion   string
+}
+
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// certReloader serves the certificates from disk and reloads them on the
+// next handshake once any of the files changes, so rotated certificates
+// are picked up without a restart.
+type certReloader struct {
+	lock     sync.Mutex
+	config   TLSConfig
+	modTimes []time.Time
+	current  *tls.Config
+}
+
+func newCertReloader(config TLSConfig) (*certReloader, error) {
+	if _, ok := tlsVersions[config.MinVersion]; !ok {
+		return nil, errors.Errorf("Unknown TLS version %q", config.MinVersion)
+	}
+	reloader := &certReloader{config: config}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+func (r *certReloader) changed() bool {
+	for i, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) reload() error {
+	modTimes := []time.Time{}
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return errors.Wrap(err, "Reading TLS files")
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return errors.Wrap(err, "Loading TLS certificate")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tlsVersions[r.config.MinVersion],
+		NextProtos:   []string{"h2"},
+	}
+	if r.config.ClientCAFile != "" {
+		content, err := ioutil.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return errors.Wrap(err, "Loading client CA")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return errors.New("Loading client CA: no certificates found")
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.current = config
+	r.modTimes = modTimes
+	return nil
+}
+
+// configForClient keeps the previous certificates when the new ones can not
+// be loaded, e.g. while only one of the files is rotated.
+func (r *certReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.changed() {
+		if err := r.reload(); err != nil {
+			log.Printf("Keeping previous TLS certificates: %v", err)
+		} else {
+			log.Printf("TLS certificates reloaded")
+		}
+	}
+	return r.current, nil
+}
+
+func (s *Service) serverCredentials() (credentials.TransportCredentials, error) {
+	config := s.currentConfig().TLS
+	if config.CertFile == "" {
+		return nil, nil
+	}
+	reloader, err := newCertReloader(config)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:         tlsVersions[config.MinVersion],
+		GetConfigForClient: reloader.configForClient,
+	}), nil
+}
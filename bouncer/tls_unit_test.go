@@ -0,0 +1,160 @@
+package bouncer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a certificate signed by the CA and its key into dir.
+func (ca *testCA) issue(t *testing.T, dir string, commonName string) (certFile string, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	certFile = filepath.Join(dir, commonName+".crt")
+	keyFile = filepath.Join(dir, commonName+".key")
+	require.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func TestTLS(t *testing.T) {
+	ca := newTestCA(t)
+	startServer := func(t *testing.T, config TLSConfig) *Service {
+		service := newTestService(t, func(c *ConfigStruct) {
+			c.TLS = config
+		})
+		startTestService(t, service, false)
+		return service
+	}
+	authorize := func(service *Service, config *tls.Config) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, service.listener.Addr().String(),
+			grpc.WithTransportCredentials(credentials.NewTLS(config)))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = NewBouncerClient(conn).Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+		return err
+	}
+	serverName := func(t *testing.T, service *Service) string {
+		conn, err := tls.Dial("tcp", service.listener.Addr().String(), &tls.Config{RootCAs: ca.pool(), NextProtos: []string{"h2"}})
+		require.Nil(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	t.Run("server certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := ca.issue(t, dir, "server")
+		service := startServer(t, TLSConfig{CertFile: certFile, KeyFile: keyFile})
+
+		require.Nil(t, authorize(service, &tls.Config{RootCAs: ca.pool()}))
+		require.Error(t, authorize(service, &tls.Config{RootCAs: x509.NewCertPool()}))
+	})
+
+	t.Run("client certificate required", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := ca.issue(t, dir, "server")
+		caFile := filepath.Join(dir, "ca.crt")
+		require.Nil(t, ioutil.WriteFile(caFile, ca.pem, 0o600))
+		service := startServer(t, TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, MinVersion: "1.3"})
+
+		clientCertFile, clientKeyFile := ca.issue(t, dir, "client")
+		clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		require.Nil(t, err)
+		require.Nil(t, authorize(service, &tls.Config{RootCAs: ca.pool(), Certificates: []tls.Certificate{clientCert}}))
+		require.Error(t, authorize(service, &tls.Config{RootCAs: ca.pool()}))
+		require.Error(t, authorize(service, &tls.Config{
+			RootCAs:      ca.pool(),
+			Certificates: []tls.Certificate{clientCert},
+			MaxVersion:   tls.VersionTLS12,
+		}))
+	})
+
+	t.Run("certificate rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := ca.issue(t, dir, "server")
+		service := startServer(t, TLSConfig{CertFile: certFile, KeyFile: keyFile})
+		require.Equal(t, "server", serverName(t, service))
+
+		rotatedCertFile, rotatedKeyFile := ca.issue(t, dir, "rotated")
+		for source, target := range map[string]string{rotatedCertFile: certFile, rotatedKeyFile: keyFile} {
+			require.Nil(t, os.Rename(source, target))
+			future := time.Now().Add(time.Minute)
+			require.Nil(t, os.Chtimes(target, future, future))
+		}
+		require.Equal(t, "rotated", serverName(t, service))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newCertReloader(TLSConfig{CertFile: "missing.crt", KeyFile: "missing.key"})
+		require.Error(t, err)
+		_, err = newCertReloader(TLSConfig{MinVersion: "1.0"})
+		require.Error(t, err)
+	})
+}
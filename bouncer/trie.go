@@ -0,0 +1,130 @@
+package bouncer
+
+import (
+	"net"
+)
+
+type trieNode struct {
+	children [2]*trieNode
+	subnet   *net.IPNet
+}
+
+// subnetTrie is a binary radix trie of subnets with separate roots for
+// IPv4 and IPv6. Lookup returns the longest matching prefix in O(prefix length).
+type subnetTrie struct {
+	root4 trieNode
+	root6 trieNode
+	size  int
+}
+
+func newSubnetTrie(subnets []net.IPNet) *subnetTrie {
+	trie := &subnetTrie{}
+	for _, subnet := range subnets {
+		trie.Insert(subnet)
+	}
+	return trie
+}
+
+func (t *subnetTrie) root(ip net.IP) (*trieNode, net.IP) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &t.root4, ip4
+	}
+	return &t.root6, ip.To16()
+}
+
+func bitAt(ip net.IP, i int) int {
+	return int(ip[i/8]>>(7-uint(i%8))) & 1
+}
+
+// Insert adds the subnet and reports whether it was not there yet.
+func (t *subnetTrie) Insert(subnet net.IPNet) bool {
+	node, ip := t.root(subnet.IP)
+	ones, _ := subnet.Mask.Size()
+	if len(subnet.Mask) == net.IPv6len && len(ip) == net.IPv4len {
+		ones -= 96
+	}
+
+	for i := 0; i < ones; i++ {
+		bit := bitAt(ip, i)
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode{}
+		}
+		node = node.children[bit]
+	}
+	if node.subnet != nil {
+		return false
+	}
+
+	node.subnet = &net.IPNet{IP: subnet.IP, Mask: subnet.Mask}
+	t.size++
+	return true
+}
+
+// Remove deletes the subnet, prunes the emptied branch and reports
+// whether the subnet was there.
+func (t *subnetTrie) Remove(subnet net.IPNet) bool {
+	node, ip := t.root(subnet.IP)
+	ones, _ := subnet.Mask.Size()
+	if len(subnet.Mask) == net.IPv6len && len(ip) == net.IPv4len {
+		ones -= 96
+	}
+
+	path := make([]*trieNode, 0, ones+1)
+	path = append(path, node)
+	for i := 0; i < ones; i++ {
+		node = node.children[bitAt(ip, i)]
+		if node == nil {
+			return false
+		}
+		path = append(path, node)
+	}
+	if node.subnet == nil {
+		return false
+	}
+
+	node.subnet = nil
+	t.size--
+	for i := len(path) - 1; i > 0; i-- {
+		cur := path[i]
+		if cur.subnet != nil || cur.children[0] != nil || cur.children[1] != nil {
+			break
+		}
+		path[i-1].children[bitAt(ip, i-1)] = nil
+	}
+	return true
+}
+
+func (t *subnetTrie) Lookup(ip net.IP) (*net.IPNet, bool) {
+	return t.LookupFunc(ip, nil)
+}
+
+// LookupFunc returns the longest matching prefix that keep accepts, a nil
+// keep accepts every prefix.
+func (t *subnetTrie) LookupFunc(ip net.IP, keep func(*net.IPNet) bool) (*net.IPNet, bool) {
+	if t == nil || ip == nil {
+		return nil, false
+	}
+
+	node, ip := t.root(ip)
+	var match *net.IPNet
+	if node.subnet != nil && (keep == nil || keep(node.subnet)) {
+		match = node.subnet
+	}
+	for i := 0; i < len(ip)*8; i++ {
+		node = node.children[bitAt(ip, i)]
+		if node == nil {
+			break
+		}
+		if node.subnet != nil && (keep == nil || keep(node.subnet)) {
+			match = node.subnet
+		}
+	}
+	return match, match != nil
+}
+
+func (t *subnetTrie) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
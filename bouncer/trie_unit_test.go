@@ -0,0 +1,99 @@
+package bouncer
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubnetTrie(t *testing.T) {
+	parse := func(cidr string) net.IPNet {
+		_, subnet, err := net.ParseCIDR(cidr)
+		require.Nil(t, err)
+		return *subnet
+	}
+
+	t.Run("longest prefix match", func(t *testing.T) {
+		trie := newSubnetTrie([]net.IPNet{parse("10.0.0.0/8"), parse("10.1.0.0/16"), parse("2001:db8::/32")})
+
+		match, ok := trie.Lookup(net.ParseIP("10.1.2.3"))
+		require.True(t, ok)
+		require.Equal(t, "10.1.0.0/16", match.String())
+
+		match, ok = trie.Lookup(net.ParseIP("10.2.2.3"))
+		require.True(t, ok)
+		require.Equal(t, "10.0.0.0/8", match.String())
+
+		match, ok = trie.Lookup(net.ParseIP("::ffff:10.2.2.3"))
+		require.True(t, ok)
+		require.Equal(t, "10.0.0.0/8", match.String())
+
+		match, ok = trie.Lookup(net.ParseIP("2001:db8::1"))
+		require.True(t, ok)
+		require.Equal(t, "2001:db8::/32", match.String())
+
+		_, ok = trie.Lookup(net.ParseIP("11.0.0.1"))
+		require.False(t, ok)
+		_, ok = trie.Lookup(nil)
+		require.False(t, ok)
+
+		match, ok = trie.LookupFunc(net.ParseIP("10.1.2.3"), func(subnet *net.IPNet) bool {
+			return subnet.String() != "10.1.0.0/16"
+		})
+		require.True(t, ok)
+		require.Equal(t, "10.0.0.0/8", match.String())
+	})
+
+	t.Run("insert and remove", func(t *testing.T) {
+		trie := newSubnetTrie(nil)
+		require.True(t, trie.Insert(parse("192.168.0.0/16")))
+		require.False(t, trie.Insert(parse("192.168.0.0/16")))
+		require.True(t, trie.Insert(parse("192.168.1.0/24")))
+		require.Equal(t, 2, trie.Len())
+
+		require.True(t, trie.Remove(parse("192.168.1.0/24")))
+		require.False(t, trie.Remove(parse("192.168.1.0/24")))
+		require.False(t, trie.Remove(parse("192.0.0.0/8")))
+		require.Equal(t, 1, trie.Len())
+
+		match, ok := trie.Lookup(net.ParseIP("192.168.1.1"))
+		require.True(t, ok)
+		require.Equal(t, "192.168.0.0/16", match.String())
+
+		require.True(t, trie.Remove(parse("192.168.0.0/16")))
+		require.Equal(t, trieNode{}, trie.root4)
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		trie := newSubnetTrie([]net.IPNet{parse("0.0.0.0/0")})
+		_, ok := trie.Lookup(net.ParseIP("8.8.8.8"))
+		require.True(t, ok)
+		_, ok = trie.Lookup(net.ParseIP("2001:db8::1"))
+		require.False(t, ok)
+	})
+}
+
+func TestListIndex(t *testing.T) {
+	_, duplicate, _ := net.ParseCIDR("10.0.0.0/24")
+	service := &Service{config: ConfigStruct{Lists: map[string][]net.IPNet{"black": {*duplicate, *duplicate}}}}
+	service.initLists()
+	require.Len(t, service.config.Lists["black"], 1)
+
+	for i := 1; i < 200; i++ {
+		require.Nil(t, service.AddSubnetToList(fmt.Sprintf("10.0.%d.0/24", i), "black"))
+	}
+	for i := 0; i < 200; i += 3 {
+		require.Nil(t, service.RemoveSubnetFromList(fmt.Sprintf("10.0.%d.0/24", i), "black"))
+	}
+
+	subnets := service.config.Lists["black"]
+	require.Len(t, subnets, 133)
+	require.Len(t, service.listIndex["black"], 133)
+	for i, subnet := range subnets {
+		require.Equal(t, i, service.listIndex["black"][subnet.String()])
+	}
+	_, ok := service.listIndex["black"]["10.0.3.0/24"]
+	require.False(t, ok)
+}
@@ -0,0 +1,113 @@
+package bouncer
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxLoginLength    = 256
+	maxPasswordLength = 1024
+	maxAddressLength  = 64
+	maxCommentLength  = 256
+)
+
+var ErrSubnetNotFound = errors.New("Subnet is not in the list")
+
+// checkField rejects a missing required value and a value over max bytes
+// with codes.InvalidArgument.
+func checkField(name string, value string, required bool, max int) error {
+	if required && value == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", name)
+	}
+	if len(value) > max {
+		return status.Errorf(codes.InvalidArgument, "%s is longer than %d bytes", name, max)
+	}
+	return nil
+}
+
+func validateAuthRequest(in *AuthRequest) error {
+	if err := checkField("login", in.Login, true, maxLoginLength); err != nil {
+		return err
+	}
+	if err := checkField("password", in.Password, true, maxPasswordLength); err != nil {
+		return err
+	}
+	return checkField("ip", in.Ip, true, maxAddressLength)
+}
+
+func validateDropBucket(in *DropBucketParams) error {
+	if in.Login == "" && in.Ip == "" {
+		return status.Error(codes.InvalidArgument, "login or ip is required")
+	}
+	if err := checkField("login", in.Login, false, maxLoginLength); err != nil {
+		return err
+	}
+	return checkField("ip", in.Ip, false, maxAddressLength)
+}
+
+func validateBucketStateRequest(in *BucketStateRequest) error {
+	if in.Login == "" && in.Password == "" && in.Ip == "" {
+		return status.Error(codes.InvalidArgument, "login, password or ip is required")
+	}
+	if err := checkField("login", in.Login, false, maxLoginLength); err != nil {
+		return err
+	}
+	if err := checkField("password", in.Password, false, maxPasswordLength); err != nil {
+		return err
+	}
+	return checkField("ip", in.Ip, false, maxAddressLength)
+}
+
+// validateSubnet checks the subnet of a list change. The ttl and the comment
+// are only checked when the subnet is added.
+func validateSubnet(in *Subnet, adding bool) error {
+	if err := checkField("subnet", in.Subnet, true, maxAddressLength); err != nil {
+		return err
+	}
+	if _, _, err := net.ParseCIDR(in.Subnet); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid subnet %q", in.Subnet)
+	}
+	if !adding {
+		return nil
+	}
+	if err := checkField("comment", in.Comment, false, maxCommentLength); err != nil {
+		return err
+	}
+	if in.Ttl != nil {
+		if err := in.Ttl.CheckValid(); err != nil || in.Ttl.AsDuration() < 0 {
+			return status.Error(codes.InvalidArgument, "ttl must be a valid non-negative duration")
+		}
+	}
+	return nil
+}
+
+func validateWatchRequest(in *WatchRequest) error {
+	if err := checkField("login", in.Login, false, maxLoginLength); err != nil {
+		return err
+	}
+	return checkField("ip prefix", in.IpPrefix, false, maxAddressLength)
+}
+
+// canonicalSubnet returns the network of the subnet as the lists keep it,
+// or the subnet as it is when it does not parse.
+func canonicalSubnet(subnet string) string {
+	if _, network, err := net.ParseCIDR(subnet); err == nil {
+		return network.String()
+	}
+	return subnet
+}
+
+// listChangeStatus converts the error of a list change into a status.
+func listChangeStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Cause(err) == ErrSubnetNotFound {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,213 @@
+package bouncer
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newValidationService(t *testing.T) *Service {
+	service := newTestService(t, func(config *ConfigStruct) {
+		config.Limit = map[string]int{"login": 2, "password": 100, "ip": 1000, "subnet": 1000}
+		config.IPPrefix = IPPrefixConfig{V4: 24, V6: 64}
+	})
+	service.initValues()
+	return service
+}
+
+type pageStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *pageStream) Context() context.Context    { return s.ctx }
+func (s *pageStream) SendMsg(interface{}) error   { return nil }
+func (s *pageStream) Send(page *SubnetPage) error { return nil }
+
+type eventStream struct {
+	pageStream
+}
+
+func (s *eventStream) Send(event *Event) error { return nil }
+
+// requireStatus fails unless err is nil or carries one of the codes.
+func requireStatus(t *testing.T, err error, allowed ...codes.Code) {
+	if err == nil {
+		return
+	}
+	code := status.Code(err)
+	for _, allowedCode := range allowed {
+		if code == allowedCode {
+			return
+		}
+	}
+	t.Fatalf("unexpected error %v", err)
+}
+
+func TestValidation(t *testing.T) {
+	service := newValidationService(t)
+	ctx := context.Background()
+	long := strings.Repeat("a", maxPasswordLength+1)
+
+	t.Run("authorization", func(t *testing.T) {
+		for _, request := range []*AuthRequest{
+			{Password: "password", Ip: "192.0.2.1"},
+			{Login: "login", Ip: "192.0.2.1"},
+			{Login: "login", Password: "password"},
+			{Login: long, Password: "password", Ip: "192.0.2.1"},
+			{Login: "login", Password: long, Ip: "192.0.2.1"},
+			{Login: "login", Password: "password", Ip: "192.0.2.1/24"},
+		} {
+			_, err := service.Authorization(ctx, request)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), request.String())
+		}
+	})
+
+	t.Run("drop bucket", func(t *testing.T) {
+		_, err := service.DropBucket(ctx, &DropBucketParams{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: long})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: "unknown", Ip: "192.0.2.1"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = service.Authorization(ctx, &AuthRequest{Login: "login", Password: "password", Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: "login"})
+		require.Nil(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Ip: "192.0.2.1"})
+		require.Nil(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: "login", Ip: "192.0.2.1"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("list changes", func(t *testing.T) {
+		for _, subnet := range []*Subnet{
+			{},
+			{Subnet: "192.0.2.1"},
+			{Subnet: "192.0.2.0/24", Comment: long},
+			{Subnet: "192.0.2.0/24", Ttl: durationpb.New(-time.Second)},
+			{Subnet: "192.0.2.0/24", Ttl: &durationpb.Duration{Seconds: 1, Nanos: -1}},
+		} {
+			_, err := service.AddBlackList(ctx, subnet)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), subnet.String())
+			_, err = service.AddWhiteList(ctx, subnet)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), subnet.String())
+		}
+
+		_, err := service.RemoveBlackList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		_, err = service.AddWhiteList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Nil(t, err)
+		_, err = service.RemoveBlackList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		_, err = service.RemoveWhiteList(ctx, &Subnet{Subnet: "192.0.2.0/24"})
+		require.Nil(t, err)
+		_, err = service.RemoveWhiteList(ctx, &Subnet{Subnet: "not a subnet"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		_, err := service.CheckIP(ctx, &CheckIPRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.GetBucketState(ctx, &BucketStateRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = service.GetBucketState(ctx, &BucketStateRequest{Password: long})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		err = service.ListBlackList(&ListRequest{PageToken: long}, &pageStream{ctx: ctx})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		err = service.Watch(&WatchRequest{Login: long}, &eventStream{pageStream{ctx: ctx}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// mutate returns one of the seeds with a few random bytes inserted or removed.
+func mutate(r *rand.Rand, seeds []string) string {
+	value := []byte(seeds[r.Intn(len(seeds))])
+	for i := r.Intn(4); i > 0; i-- {
+		position := r.Intn(len(value) + 1)
+		if r.Intn(2) == 0 || position == len(value) {
+			value = append(value[:position], append([]byte{byte(r.Intn(256))}, value[position:]...)...)
+		} else {
+			value = append(value[:position], value[position+1:]...)
+		}
+	}
+	return string(value)
+}
+
+func TestRandomRequests(t *testing.T) {
+	const runs = 1000
+	r := rand.New(rand.NewSource(1))
+	long := strings.Repeat("a", maxPasswordLength+1)
+	logins := []string{"", "login", "0123", long}
+	addresses := []string{"", "192.0.2.1", "2001:db8::1", "::ffff:192.0.2.1", "192.0.2.0/24", "2001:db8::/64", "0.0.0.0/0", long}
+	ctx := context.Background()
+
+	t.Run("authorization", func(t *testing.T) {
+		service := newValidationService(t)
+		for i := 0; i < runs; i++ {
+			login, password, ip, preHashed := mutate(r, logins), mutate(r, logins), mutate(r, addresses), r.Intn(2) == 0
+			_, err := service.Authorization(ctx, &AuthRequest{Login: login, Password: password, Ip: ip, PreHashed: preHashed})
+			requireStatus(t, err, codes.InvalidArgument)
+			_, err = service.GetBucketState(ctx, &BucketStateRequest{Login: login, Password: password, Ip: ip, PreHashed: preHashed})
+			requireStatus(t, err, codes.InvalidArgument)
+			_, err = service.DropBucket(ctx, &DropBucketParams{Login: login, Ip: ip, PreHashed: preHashed})
+			requireStatus(t, err, codes.InvalidArgument, codes.NotFound)
+			_, err = service.CheckIP(ctx, &CheckIPRequest{Ip: ip})
+			requireStatus(t, err, codes.InvalidArgument)
+		}
+	})
+
+	t.Run("list changes", func(t *testing.T) {
+		service := newValidationService(t)
+		ttls := []*durationpb.Duration{
+			nil,
+			{Seconds: 60},
+			{Seconds: -1, Nanos: -5},
+			{Seconds: 315576000001, Nanos: 999999999},
+		}
+		for i := 0; i < runs; i++ {
+			subnet, comment := mutate(r, addresses), mutate(r, logins)
+			ttl := ttls[r.Intn(len(ttls))]
+			pageSize := int32(r.Intn(2100)) - 50
+			_, err := service.AddBlackList(ctx, &Subnet{Subnet: subnet, Ttl: ttl, Comment: comment})
+			requireStatus(t, err, codes.InvalidArgument)
+			_, err = service.AddWhiteList(ctx, &Subnet{Subnet: subnet, Ttl: ttl, Comment: comment})
+			requireStatus(t, err, codes.InvalidArgument)
+
+			stream := &pageStream{ctx: ctx}
+			requireStatus(t, service.ListBlackList(&ListRequest{PageSize: pageSize, PageToken: subnet}, stream), codes.InvalidArgument)
+			requireStatus(t, service.ListWhiteList(&ListRequest{PageSize: pageSize, PageToken: subnet}, stream), codes.InvalidArgument)
+
+			_, err = service.RemoveBlackList(ctx, &Subnet{Subnet: subnet})
+			requireStatus(t, err, codes.InvalidArgument, codes.NotFound)
+			_, err = service.RemoveWhiteList(ctx, &Subnet{Subnet: subnet})
+			requireStatus(t, err, codes.InvalidArgument, codes.NotFound)
+			service.expireListEntries(time.Now())
+		}
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		service := newValidationService(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		for i := 0; i < runs; i++ {
+			request := &WatchRequest{
+				Login:     mutate(r, logins),
+				IpPrefix:  mutate(r, addresses),
+				Types:     []EventType{EventType(r.Intn(10) - 2)},
+				PreHashed: r.Intn(2) == 0,
+			}
+			err := service.Watch(request, &eventStream{pageStream{ctx: cancelled}})
+			requireStatus(t, err, codes.InvalidArgument)
+		}
+	})
+}
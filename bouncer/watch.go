@@ -0,0 +1,176 @@
+package bouncer
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultWatchBuffer = 256
+
+// eventHub fans events out to Watch subscribers. Publishing never blocks:
+// an event that does not fit into the subscriber buffer is dropped and
+// counted, so slow consumers can not hold up Authorization.
+type eventHub struct {
+	lock        sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	stopped     bool
+}
+
+type subscriber struct {
+	dropped uint64
+	filter  watchFilter
+	events  chan *Event
+	done    chan struct{}
+}
+
+type watchFilter struct {
+	types    map[EventType]bool
+	prefix   *net.IPNet
+	loginKey string
+}
+
+func (h *eventHub) subscribe(filter watchFilter, buffer int) *subscriber {
+	sub := &subscriber{filter: filter, events: make(chan *Event, buffer), done: make(chan struct{})}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.stopped {
+		close(sub.done)
+		return sub
+	}
+	if h.subscribers == nil {
+		h.subscribers = map[*subscriber]struct{}{}
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (h *eventHub) unsubscribe(sub *subscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.subscribers, sub)
+}
+
+// stop ends every Watch stream, so they do not hold up graceful shutdown.
+func (h *eventHub) stop() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.stopped {
+		return
+	}
+	h.stopped = true
+	for sub := range h.subscribers {
+		close(sub.done)
+	}
+}
+
+func (h *eventHub) publish(event *Event) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if len(h.subscribers) == 0 {
+		return
+	}
+
+	event.Time = timestamppb.Now()
+	for sub := range h.subscribers {
+		if !sub.filter.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+}
+
+func (f watchFilter) match(event *Event) bool {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	if f.loginKey != "" && f.loginKey != event.LoginHash {
+		return false
+	}
+	if f.prefix != nil {
+		ip := net.ParseIP(event.Ip)
+		if ip == nil {
+			if subnetIP, _, err := net.ParseCIDR(event.Subnet); err == nil {
+				ip = subnetIP
+			}
+		}
+		if ip == nil || !f.prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) newWatchFilter(in *WatchRequest) (watchFilter, error) {
+	filter := watchFilter{types: map[EventType]bool{}}
+	for _, eventType := range in.Types {
+		if _, ok := EventType_name[int32(eventType)]; !ok || eventType == EventType_EVENT_UNSPECIFIED {
+			return filter, errors.Errorf("Unknown event type %d", eventType)
+		}
+		filter.types[eventType] = true
+	}
+	if in.IpPrefix != "" {
+		_, prefix, err := net.ParseCIDR(in.IpPrefix)
+		if err != nil {
+			ip := net.ParseIP(in.IpPrefix)
+			if ip == nil {
+				return filter, errors.Errorf("Invalid IP prefix %q", in.IpPrefix)
+			}
+			prefix = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		filter.prefix = prefix
+	}
+	if in.Login != "" {
+		loginKey, err := s.hasher.hashKey(in.Login, in.PreHashed)
+		if err != nil {
+			return filter, errors.Wrap(err, "Hashing login")
+		}
+		filter.loginKey = loginKey
+	}
+	return filter, nil
+}
+
+// Watch streams events matching the filter until the client goes away or
+// the server shuts down.
+func (s *Service) Watch(in *WatchRequest, stream BouncerAdmin_WatchServer) error {
+	if err := validateWatchRequest(in); err != nil {
+		return err
+	}
+	filter, err := s.newWatchFilter(in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	buffer := s.currentConfig().WatchBufferSize
+	if buffer <= 0 {
+		buffer = defaultWatchBuffer
+	}
+
+	sub := s.events.subscribe(filter, buffer)
+	defer s.events.unsubscribe(sub)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event := <-sub.events:
+			if dropped := atomic.SwapUint64(&sub.dropped, 0); dropped > 0 {
+				event = proto.Clone(event).(*Event)
+				event.Dropped = dropped
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
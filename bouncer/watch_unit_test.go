@@ -0,0 +1,99 @@
+package bouncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatch(t *testing.T) {
+	service := newTestService(t, func(config *ConfigStruct) {
+		config.Limit["login"] = 1
+	})
+	startTestService(t, service, false)
+	client := NewBouncerAdminClient(dialTestService(t, service.listener))
+	ctx := context.Background()
+
+	subscribers := func() int {
+		service.events.lock.RLock()
+		defer service.events.lock.RUnlock()
+		return len(service.events.subscribers)
+	}
+	watch := func(t *testing.T, request *WatchRequest) BouncerAdmin_WatchClient {
+		before := subscribers()
+		stream, err := client.Watch(ctx, request)
+		require.Nil(t, err)
+		require.Eventually(t, func() bool { return subscribers() > before }, 5*time.Second, time.Millisecond)
+		return stream
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		denied := watch(t, &WatchRequest{Types: []EventType{EventType_AUTH_DENIED}, Login: "login"})
+		lists := watch(t, &WatchRequest{IpPrefix: "192.0.2.0/24"})
+
+		for _, login := range []string{"other", "login", "login"} {
+			_, err := service.Authorization(ctx, &AuthRequest{Login: login, Password: login, Ip: "::ffff:198.51.100.1"})
+			require.Nil(t, err)
+		}
+		_, err := service.AddBlackList(ctx, &Subnet{Subnet: "203.0.113.0/24"})
+		require.Nil(t, err)
+		_, err = service.AddBlackList(ctx, &Subnet{Subnet: "192.0.2.130/25"})
+		require.Nil(t, err)
+		_, err = service.DropBucket(ctx, &DropBucketParams{Login: "login", Ip: "::ffff:192.0.2.1"})
+		require.Nil(t, err)
+
+		event, err := denied.Recv()
+		require.Nil(t, err)
+		loginKey, _ := service.hasher.hashKey("login", false)
+		require.Equal(t, EventType_AUTH_DENIED, event.Type)
+		require.Equal(t, loginKey, event.LoginHash)
+		require.Equal(t, Reason_LOGIN_LIMIT, event.Reason)
+		require.Equal(t, "198.51.100.1", event.Ip)
+		require.NotNil(t, event.Time)
+
+		event, err = lists.Recv()
+		require.Nil(t, err)
+		require.Equal(t, EventType_LIST_ADDED, event.Type)
+		require.Equal(t, "black", event.List)
+		require.Equal(t, "192.0.2.128/25", event.Subnet)
+		event, err = lists.Recv()
+		require.Nil(t, err)
+		require.Equal(t, EventType_BUCKET_DROPPED, event.Type)
+		require.Equal(t, loginKey, event.LoginHash)
+		require.Equal(t, "192.0.2.1", event.Ip)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		for _, request := range []*WatchRequest{
+			{Types: []EventType{EventType_EVENT_UNSPECIFIED}},
+			{IpPrefix: "192.0.2"},
+			{Login: "login", PreHashed: true},
+		} {
+			stream, err := client.Watch(ctx, request)
+			require.Nil(t, err)
+			_, err = stream.Recv()
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("slow subscriber", func(t *testing.T) {
+		hub := &eventHub{}
+		sub := hub.subscribe(watchFilter{}, 1)
+		for i := 0; i < 3; i++ {
+			hub.publish(&Event{Type: EventType_LIST_ADDED})
+		}
+		require.Len(t, sub.events, 1)
+		require.Equal(t, uint64(2), sub.dropped)
+	})
+
+	t.Run("shutdown ends streams", func(t *testing.T) {
+		stream := watch(t, &WatchRequest{})
+		require.Nil(t, service.ShutDown())
+		_, err := stream.Recv()
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
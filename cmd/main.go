@@ -1,10 +1,16 @@
 package main
 
 import (
+	"log"
+	"os"
+
 	bouncer "github.com/Karagar/final_project/bouncer"
 )
 
 func main() {
 	service := &bouncer.Service{}
-	service.InitService()
+	if err := service.InitService(); err != nil {
+		log.Printf("Server stopped with error: %v", err)
+		os.Exit(1)
+	}
 }
@@ -48,6 +48,8 @@ func TestRun(t *testing.T) {
 		authResponse, err := client.Authorization(ctx, &authRequest)
 		require.Nil(t, err)
 		require.False(t, authResponse.GetOk())
+		require.Equal(t, bouncer.Reason_LOGIN_LIMIT, authResponse.GetReason())
+		require.True(t, authResponse.GetRetryAfter().AsDuration() > 0)
 		_, err = client.DropBucket(ctx, &dropBucketRequest)
 		require.Nil(t, err)
 		authResponse, err = client.Authorization(ctx, &authRequest)
@@ -63,6 +65,7 @@ func TestRun(t *testing.T) {
 		authResponse, err := client.Authorization(ctx, &authRequest)
 		require.Nil(t, err)
 		require.False(t, authResponse.GetOk())
+		require.Equal(t, bouncer.Reason_BLACKLISTED, authResponse.GetReason())
 		_, err = client.RemoveBlackList(ctx, &subnetRequest)
 		require.Nil(t, err)
 		authResponse, err = client.Authorization(ctx, &authRequest)